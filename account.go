@@ -2,28 +2,37 @@ package gosdk
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
-	"strings"
-	"time"
 
 	sdkClient "github.com/cosmos/cosmos-sdk/client"
+	codecTypes "github.com/cosmos/cosmos-sdk/codec/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 	sdkTx "github.com/cosmos/cosmos-sdk/types/tx"
 	"github.com/cosmos/cosmos-sdk/types/tx/signing"
 	authSigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	authTx "github.com/cosmos/cosmos-sdk/x/auth/tx"
 	authTypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/cysic-tech/gosdk/crypto/ethsecp256k1"
 	cysicTypes "github.com/cysic-tech/gosdk/types/cysic"
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // GetAccount retrieves account information from the chain for a given signer.
 //
-// @param signer the Signer instance to retrieve the account for
+// @param signer the TxSigner to retrieve the account for
 // @return the account information as an EthAccount struct, or an error if retrieval fails
-func (s *Server) GetAccount(signer Signer) (*cysicTypes.EthAccount, error) {
-	return s.GetAccountByAddr(signer.CosmosAddr.String())
+func (s *Server) GetAccount(signer TxSigner) (*cysicTypes.EthAccount, error) {
+	return s.GetAccountCtx(context.Background(), signer)
+}
+
+// GetAccountCtx is like GetAccount but honours ctx for cancellation and deadlines.
+func (s *Server) GetAccountCtx(ctx context.Context, signer TxSigner) (*cysicTypes.EthAccount, error) {
+	return s.GetAccountByAddrCtx(ctx, signer.Address().String())
 }
 
 // GetAccountByAddr retrieves account information from the chain for a given address.
@@ -31,30 +40,51 @@ func (s *Server) GetAccount(signer Signer) (*cysicTypes.EthAccount, error) {
 // @param addr the address to retrieve the account information for
 // @return the account information as an EthAccount struct, or an error if retrieval fails
 func (s *Server) GetAccountByAddr(addr string) (*cysicTypes.EthAccount, error) {
-	if err := s.KeepGrpcConn(); err != nil {
-		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+	return s.GetAccountByAddrCtx(context.Background(), addr)
+}
+
+// GetAccountByAddrCtx is like GetAccountByAddr but honours ctx for cancellation and deadlines.
+func (s *Server) GetAccountByAddrCtx(ctx context.Context, addr string) (*cysicTypes.EthAccount, error) {
+	if err := s.KeepGrpcConnCtx(ctx); err != nil {
+		s.log().Error("error when keep grpc conn", "endpoint", s.endPoint, "err", err)
 		return nil, err
 	}
 
+	return s.getAccountByAddr(ctx, s.conn(), addr)
+}
+
+// getAccountByAddr queries the account of addr through the given connection.
+//
+// @param ctx the context controlling cancellation and deadline
+// @param conn the connection to query through
+// @param addr the address to retrieve the account information for
+// @return the account information as an EthAccount struct, or an error if retrieval fails
+func (s *Server) getAccountByAddr(ctx context.Context, conn grpc.ClientConnInterface, addr string) (*cysicTypes.EthAccount, error) {
 	cosmosAddr, err := ConvertToCysicAddress(addr)
 	if err != nil {
-		log.Printf("error when convert addr: %v to cosmos addr, err: %v", addr, err.Error())
+		s.log().Error("error when convert addr to cosmos addr", "address", addr, "err", err)
 		return nil, err
 	}
 
-	client := authTypes.NewQueryClient(s.Conn)
+	client := authTypes.NewQueryClient(conn)
 	req := authTypes.QueryAccountRequest{Address: cosmosAddr}
-	res, err := client.Account(context.Background(), &req)
+	res, err := client.Account(ctx, &req)
 	if err != nil {
-		return nil, err
+		if status.Code(err) == codes.NotFound {
+			return nil, fmt.Errorf("%w: %v: %w", ErrAccountNotFound, cosmosAddr, err)
+		}
+		return nil, wrapCtxErr(ctx, err)
 	}
 
 	temp := &cysicTypes.EthAccount{}
 	err = temp.XXX_Unmarshal(res.Account.GetValue())
 	if err != nil {
-		log.Printf("error when new grpc client: %s\n", err.Error())
+		s.log().Error("error when unmarshal account", "address", cosmosAddr, "err", err)
 		return nil, err
 	}
+	if temp.BaseAccount != nil {
+		s.sequences.observe(cosmosAddr, temp.Sequence)
+	}
 
 	return temp, nil
 }
@@ -64,33 +94,67 @@ func (s *Server) GetAccountByAddr(addr string) (*cysicTypes.EthAccount, error) {
 // @param txBytes the signed transaction bytes
 // @return the transaction response, or an error if broadcasting fails
 func (s *Server) BroadcastTx(txBytes []byte) (*sdk.TxResponse, error) {
-	conn, err := grpc.Dial(s.EndPoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	return s.BroadcastTxCtx(context.Background(), txBytes)
+}
+
+// BroadcastTxCtx is like BroadcastTx but honours ctx for cancellation and deadlines.
+func (s *Server) BroadcastTxCtx(ctx context.Context, txBytes []byte) (*sdk.TxResponse, error) {
+	return s.BroadcastTxWithModeCtx(ctx, txBytes, BroadcastModeSync)
+}
+
+// BroadcastTxWithMode broadcasts a signed transaction to the network in the given mode.
+//
+// @param txBytes the signed transaction bytes
+// @param mode the broadcast mode
+// @return the transaction response, or an error if broadcasting fails
+func (s *Server) BroadcastTxWithMode(txBytes []byte, mode BroadcastMode) (*sdk.TxResponse, error) {
+	return s.BroadcastTxWithModeCtx(context.Background(), txBytes, mode)
+}
+
+// BroadcastTxWithModeCtx is like BroadcastTxWithMode but honours ctx for cancellation and deadlines.
+func (s *Server) BroadcastTxWithModeCtx(ctx context.Context, txBytes []byte, mode BroadcastMode) (*sdk.TxResponse, error) {
+	if err := s.KeepGrpcConnCtx(ctx); err != nil {
+		s.log().Error("error when keep grpc conn", "endpoint", s.endPoint, "err", err)
+		return nil, err
+	}
+
+	resp, err := s.broadcastTx(ctx, s.conn(), txBytes, mode)
+	s.metrics.observeBroadcast(resp, err, nil)
+	return resp, err
+}
+
+// broadcastTx broadcasts signed transaction bytes through the given connection.
+//
+// @param ctx the context controlling cancellation and deadline
+// @param conn the connection to broadcast through
+// @param txBytes the signed transaction bytes
+// @param mode the broadcast mode
+// @return the transaction response, or an error if broadcasting fails
+func (s *Server) broadcastTx(ctx context.Context, conn grpc.ClientConnInterface, txBytes []byte, mode BroadcastMode) (*sdk.TxResponse, error) {
+	protoMode, err := mode.proto()
 	if err != nil {
-		log.Printf("error when new grpc client: %s\n", err.Error())
 		return nil, err
 	}
-	defer conn.Close()
 
 	client := sdkTx.NewServiceClient(conn)
-
-	res, err := client.BroadcastTx(context.Background(), &sdkTx.BroadcastTxRequest{
+	res, err := client.BroadcastTx(ctx, &sdkTx.BroadcastTxRequest{
 		TxBytes: txBytes,
-		Mode:    sdkTx.BroadcastMode_BROADCAST_MODE_SYNC,
+		Mode:    protoMode,
 	})
 	if errRes := sdkClient.CheckTendermintError(err, txBytes); errRes != nil {
 		return errRes, nil
 	}
 	if err != nil {
-		return nil, err
+		return nil, wrapCtxErr(ctx, err)
 	}
 
 	return res.TxResponse, err
 }
 
-func (s *Server) getAccountNumberAndSequenceOnChain(address sdk.AccAddress) (exist bool, accNumber uint64, sequence uint64, err error) {
-	temp, err := s.GetAccountByAddr(address.String())
+func (s *Server) getAccountNumberAndSequenceOnChain(ctx context.Context, conn grpc.ClientConnInterface, address sdk.AccAddress) (exist bool, accNumber uint64, sequence uint64, err error) {
+	temp, err := s.getAccountByAddr(ctx, conn, address.String())
 	if err != nil {
-		log.Printf("error when GetAccountByAddr: %v, err: %v", address.String(), err.Error())
+		s.log().Error("error when get account", "address", address.String(), "err", err)
 		return false, 0, 0, err
 	}
 
@@ -99,190 +163,505 @@ func (s *Server) getAccountNumberAndSequenceOnChain(address sdk.AccAddress) (exi
 	return true, accNumber, sequence, nil
 }
 
+// BroadcastMsgs signs msgList as one transaction and broadcasts it, returning the hash
+// together with the account number and sequence it was signed with.
+//
+// @param signer the TxSigner used to sign the transaction
+// @param msgList the messages to include in the transaction
+// @param opts the options of this transaction, e.g. WithTxFeeStrategy or WithBroadcastMode
+// @return the broadcast result, or an error if the transaction fails
+func (s *Server) BroadcastMsgs(signer TxSigner, msgList []sdk.Msg, opts ...TxOption) (*BroadcastResult, error) {
+	return s.BroadcastMsgsCtx(context.Background(), signer, msgList, opts...)
+}
+
+// BroadcastMsgsCtx is like BroadcastMsgs but honours ctx for cancellation and deadlines.
+func (s *Server) BroadcastMsgsCtx(ctx context.Context, signer TxSigner, msgList []sdk.Msg, opts ...TxOption) (*BroadcastResult, error) {
+	if len(msgList) == 0 {
+		return nil, fmt.Errorf("msg list is empty")
+	}
+
+	return s.broadcastMsgs(ctx, signer, msgList, opts...)
+}
+
 // buildAndBroadcastCosmosTx builds a Cosmos transaction with the provided signer and messages, then broadcasts it to the network.
 //
-// @param signer the Signer instance used to sign the transaction
+// @param ctx the context controlling cancellation and deadline of every gRPC call
+// @param signer the TxSigner used to sign the transaction
 // @param msgList list of messages to include in the transaction
 // @return the transaction hash as a string, or an error if the transaction fails
-func (s *Server) buildAndBroadcastCosmosTx(signer Signer, msgList []sdk.Msg) (string, error) {
+func (s *Server) buildAndBroadcastCosmosTx(ctx context.Context, signer TxSigner, msgList []sdk.Msg) (string, error) {
+	result, err := s.broadcastMsgs(ctx, signer, msgList)
+	if err != nil {
+		return "", err
+	}
+
+	return result.TxHash, nil
+}
+
+// broadcastMsgs is the transaction pipeline behind every write method: it resolves the
+// account number and sequence, signs, broadcasts and retries according to the retry policy.
+// The transaction is traced as a span with a child span per stage.
+//
+// @param ctx the context controlling cancellation and deadline of every gRPC call
+// @param signer the TxSigner used to sign the transaction
+// @param msgList list of messages to include in the transaction
+// @param opts the options of this transaction
+// @return the broadcast result, or an error if the transaction fails
+func (s *Server) broadcastMsgs(ctx context.Context, signer TxSigner, msgList []sdk.Msg, opts ...TxOption) (_ *BroadcastResult, err error) {
+	txOpts := s.newTxOptions(opts)
+	txOpts.dropSelfFeePayer(signer)
+
+	ctx, span := s.startTxSpan(ctx, signer, msgList, txOpts)
+	defer func() { endSpan(span, err) }()
+
+	if txOpts.signMode == SignModeEIP712 {
+		if err := validateEIP712Msgs(signer, msgList); err != nil {
+			return nil, err
+		}
+	}
+
 	for _, msg := range msgList {
 		if err := msg.ValidateBasic(); err != nil {
-			log.Printf("error when validate basic for msg: %v, err: %v\n", msg, err.Error())
-			return "", err
+			s.log().Error("error when validate basic for msg", "msg", msg, "err", err)
+			return nil, err
 		}
 	}
 
-	signerPriv := signer.privateKey
-	accAddr := signer.CosmosAddr
-	signerPubKey := signerPriv.PubKey()
+	accAddr := signer.Address()
+	if err := s.KeepGrpcConnCtx(ctx); err != nil {
+		s.log().Error("error when keep grpc conn", "endpoint", s.endPoint, "err", err)
+		return nil, err
+	}
+
+	// held across all attempts, so one account's txs enter the mempool in order and a
+	// retried tx is never re-signed with a sequence another tx has taken meanwhile
+	seqStates, unlock := s.sequences.lock(txOpts.accounts(signer)...)
+	defer unlock()
+	seqs := seqStates[0]
+
+	var (
+		txBytes  []byte
+		fee      sdk.Coins
+		result   BroadcastResult
+		signed   bool
+		resync   bool
+		useNonce = true
+	)
+	err = s.retryPolicy.do(ctx, s.log(), "broadcast tx", func() error {
+		// account lookup and broadcast go to the same endpoint so the sequence stays consistent
+		conn := s.accountConn(accAddr.String())
+
+		if !signed || resync {
+			stageCtx, endStage := s.startStage(ctx, stageAccountLookup)
+			accNumber, sequence, err := s.nextSequence(stageCtx, conn, signer, seqs, useNonce)
+			if err == nil && txOpts.feePayer != nil {
+				err = s.resolveFeePayer(stageCtx, conn, txOpts, seqStates[1], useNonce)
+			}
+			endStage(err)
+			if err != nil {
+				return err
+			}
+
+			stageCtx, endStage = s.startStage(ctx, stageFeeQuote)
+			quote, err := s.feeQuote(stageCtx, txOpts)
+			endStage(err)
+			if err != nil {
+				return err
+			}
 
-	exist, accNumber, sequence, err := s.getAccountNumberAndSequenceOnChain(accAddr)
+			stageCtx, endStage = s.startStage(ctx, stageGasEstimate)
+			estimate, err := s.estimateGas(stageCtx, conn, signer, sequence, msgList, quote, txOpts)
+			endStage(err)
+			if err != nil {
+				if errors.Is(err, ErrSequenceMismatch) {
+					// simulate again with the sequence the chain expects
+					useNonce = false
+					sequenceMismatch(seqStates, txOpts, err)
+				}
+				return err
+			}
+			span.SetAttributes(
+				attribute.Int64("cysic.tx.gas_used", int64(estimate.GasUsed)),
+				attribute.Int64("cysic.tx.gas_limit", int64(estimate.GasLimit)),
+				attribute.String("cysic.tx.fee", estimate.Fee.String()),
+			)
+
+			stageCtx, endStage = s.startStage(ctx, stageSign)
+			txBytes, err = s.signTx(stageCtx, signer, accNumber, sequence, msgList, estimate.GasLimit, quote, txOpts)
+			endStage(err)
+			if err != nil {
+				return err
+			}
+			fee = estimate.Fee
+			result = BroadcastResult{AccountNumber: accNumber, Sequence: sequence}
+			signed, resync = true, false
+		}
+
+		stageCtx, endStage := s.startStage(ctx, stageBroadcast)
+		resp, err := s.broadcastTx(stageCtx, conn, txBytes, txOpts.broadcastMode)
+		if err != nil {
+			endStage(err)
+			s.metrics.observeBroadcast(nil, err, nil)
+			s.log().Error("error when broadcast tx", "err", err)
+			return err
+		}
+		// a previous attempt already reached the mempool
+		if resp.Codespace == sdkerrors.RootCodespace && resp.Code == sdkerrors.ErrTxInMempoolCache.ABCICode() {
+			resp.Code = 0
+		}
+		s.metrics.observeBroadcast(resp, nil, fee)
+		txErr := TxResponseError(resp)
+		endStage(txErr)
+		if txErr != nil {
+			s.log().Warn("tx rejected", "address", accAddr.String(), "txHash", resp.TxHash, "codespace", resp.Codespace, "code", resp.Code, "rawLog", resp.RawLog)
+			if errors.Is(txErr, ErrSequenceMismatch) {
+				// re-sign with the sequence the chain expects
+				resync = true
+				useNonce = false
+				sequenceMismatch(seqStates, txOpts, txErr)
+			}
+			return txErr
+		}
+
+		if seqs != nil {
+			seqs.commit(result.Sequence, resp.TxHash)
+		}
+		if txOpts.feePayer != nil && seqStates[1] != nil {
+			seqStates[1].commit(txOpts.feePayer.sequence, resp.TxHash)
+		}
+		result.TxHash = resp.TxHash
+		span.SetAttributes(attribute.String("cysic.tx.hash", resp.TxHash), attribute.Int64("cysic.tx.sequence", int64(result.Sequence)))
+		s.log().Info("tx broadcast", "address", accAddr.String(), "txHash", resp.TxHash, "sequence", result.Sequence)
+		return nil
+	})
 	if err != nil {
-		log.Printf("error when get accInfo on chain, addr: %v, err: %v\n", accAddr.String(), err.Error())
-		return "", err
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// nextSequence resolves the account number and sequence of the next transaction of signer,
+// from the SequenceManager when enabled and from chain otherwise.
+//
+// @param ctx the context controlling cancellation and deadline
+// @param conn the connection to query the account through
+// @param signer the TxSigner the transaction is for
+// @param seqs the locked local sequence state, or nil without a SequenceManager
+// @param useNonce whether the Nonce of a Signer may raise the on-chain sequence
+// @return the account number and sequence, or an error if the account can't be read
+func (s *Server) nextSequence(ctx context.Context, conn grpc.ClientConnInterface, signer TxSigner, seqs *accountSequence, useNonce bool) (uint64, uint64, error) {
+	if seqs != nil && seqs.synced {
+		return seqs.accountNumber, seqs.next, nil
+	}
+
+	accAddr := signer.Address()
+	exist, accNumber, sequence, err := s.getAccountNumberAndSequenceOnChain(ctx, conn, accAddr)
+	if err != nil {
+		s.log().Error("error when get accInfo on chain", "address", accAddr.String(), "err", err)
+		return 0, 0, err
 	}
 	if !exist {
-		log.Printf("error account: %v not exist", accAddr.String())
-		return "", fmt.Errorf("account %v not exist", accAddr.String())
+		s.log().Error("account not found", "address", accAddr.String())
+		return 0, 0, fmt.Errorf("%w: %v", ErrAccountNotFound, accAddr.String())
 	}
 
-	if signer.Nonce != 0 && signer.Nonce > sequence {
-		sequence = signer.Nonce
+	if nonce := signerNonce(signer); useNonce && nonce != 0 && nonce > sequence {
+		sequence = nonce
 	}
-	txBuilder, bytesToSign, err := s.GetBytesToSign(signer, accNumber, sequence, msgList)
-	if err != nil {
-		log.Printf("error when get wait sign tx, err: %v\n", err.Error())
-		return "", err
+	if seqs != nil {
+		seqs.sync(accNumber, sequence)
+	}
+
+	return accNumber, sequence, nil
+}
+
+// sequenceMismatch updates the locked local sequences of a transaction the chain rejected for
+// a wrong sequence, in simulation or at broadcast, so that the next attempt uses the sequence
+// the chain expects.
+//
+// @param seqStates the locked sequence states of the accounts of the transaction, nil entries without a SequenceManager
+// @param o the options of the transaction
+// @param err the rejection, a *TxError whose log names the expected sequence
+func sequenceMismatch(seqStates []*accountSequence, o *txOptions, err error) {
+	if o.feePayer != nil {
+		// the log does not tell whose sequence was wrong
+		for _, state := range seqStates {
+			if state != nil {
+				state.desync()
+			}
+		}
+		return
+	}
+
+	var txErr *TxError
+	if seqStates[0] != nil && errors.As(err, &txErr) {
+		seqStates[0].mismatch(txErr.Log)
+	}
+}
+
+// signTx builds the transaction for msgList and signs it with signer, and with the fee payer of o if any.
+//
+// @param ctx the context controlling cancellation and deadline
+// @param signer the TxSigner used to sign the transaction
+// @param accNumber the account number of the signer
+// @param sequence the sequence number of the signer
+// @param msgList list of messages to include in the transaction
+// @param gasLimit the gas limit of the transaction
+// @param quote the gas price of the transaction
+// @param o the options of the transaction
+// @return the encoded signed transaction, or an error if building or signing fails
+func (s *Server) signTx(ctx context.Context, signer TxSigner, accNumber, sequence uint64, msgList []sdk.Msg, gasLimit uint64, quote FeeQuote, o *txOptions) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
-	sigBytes, err := signerPriv.Sign(bytesToSign)
+	txBuilder, err := s.newTxBuilder(signer, sequence, msgList, gasLimit, quote, o)
 	if err != nil {
-		log.Printf("error when sign msg, err: %v\n", err.Error())
-		return "", err
+		return nil, err
 	}
 
-	// Construct the SignatureV2 struct
-	sigData := signing.SingleSignatureData{
-		SignMode:  signing.SignMode_SIGN_MODE_DIRECT,
-		Signature: sigBytes,
+	accounts := []signerAccount{{signer: signer, accNumber: accNumber, sequence: sequence}}
+	if o.feePayer != nil {
+		accounts = append(accounts, *o.feePayer)
 	}
-	sig := signing.SignatureV2{
-		PubKey:   signerPubKey,
-		Data:     &sigData,
-		Sequence: sequence,
+
+	sigs := make([]signing.SignatureV2, 0, len(accounts))
+	for _, account := range accounts {
+		if !supportsSignMode(account.signer, o.signMode) {
+			if _, ok := account.signer.PubKey().(*ethsecp256k1.PubKey); !ok && o.signMode == SignModeEIP712 {
+				return nil, fmt.Errorf("signer %v can't sign in mode %v: %w", account.signer.Address().String(), o.signMode, ErrNotEthKey)
+			}
+			return nil, fmt.Errorf("signer %v does not support sign mode %v", account.signer.Address().String(), o.signMode)
+		}
+
+		bytesToSign, err := s.signBytes(txBuilder, account, o)
+		if err != nil {
+			s.log().Error("error when get wait sign tx", "err", err)
+			return nil, err
+		}
+
+		// Construct the SignatureV2 struct
+		sigData := signing.SingleSignatureData{
+			SignMode: o.signMode.protoSignMode(),
+		}
+		if o.signMode == SignModeEIP712 {
+			sigBytes, err := signEIP712Hash(ctx, account.signer, bytesToSign)
+			if err != nil {
+				s.log().Error("error when sign msg", "err", err)
+				return nil, err
+			}
+			// the signer pays its own fee: its signature travels in the Web3 extension as the
+			// fee payer's, its Cosmos signature stays empty
+			if err := setWeb3Extension(txBuilder, s.chainID, account.signer.Address(), sigBytes); err != nil {
+				s.log().Error("error when set web3 extension", "err", err)
+				return nil, err
+			}
+		} else {
+			sigData.Signature, err = account.signer.SignBytes(ctx, bytesToSign)
+			if err != nil {
+				s.log().Error("error when sign msg", "err", err)
+				return nil, err
+			}
+		}
+
+		sigs = append(sigs, signing.SignatureV2{
+			PubKey:   account.signer.PubKey(),
+			Data:     &sigData,
+			Sequence: account.sequence,
+		})
 	}
 
-	err = txBuilder.SetSignatures(sig)
+	err = txBuilder.SetSignatures(sigs...)
 	if err != nil {
-		log.Printf("error when set signed bytes to tx, err: %v\n", err.Error())
-		return "", err
+		s.log().Error("error when set signed bytes to tx", "err", err)
+		return nil, err
 	}
 
 	txBytes, err := txConfig.TxEncoder()(txBuilder.GetTx())
 	if err != nil {
-		log.Printf("error when get signed tx bytes, err: %v\n", err.Error())
-		return "", err
+		s.log().Error("error when get signed tx bytes", "err", err)
+		return nil, err
 	}
 
-	resp, err := s.BroadcastTx(txBytes)
-	if err != nil {
-		log.Printf("error when broadcast tx, err: %v\n", err.Error())
-		return "", err
+	return txBytes, nil
+}
+
+// GetBytesToSign generates the bytes to sign for a transaction.
+//
+// @param signer the TxSigner used to sign the transaction
+// @param accNumber the account number of the signer
+// @param sequence the sequence number of the signer
+// @param msgList list of messages to include in the transaction
+// @param opts the options of the transaction, e.g. WithFeeGranter
+// @return the transaction builder, bytes to sign, or an error if generation fails
+func (s *Server) GetBytesToSign(signer TxSigner, accNumber, sequence uint64, msgList []sdk.Msg, opts ...TxOption) (sdkClient.TxBuilder, []byte, error) {
+	return s.GetBytesToSignCtx(context.Background(), signer, accNumber, sequence, msgList, opts...)
+}
+
+// GetBytesToSignCtx is like GetBytesToSign but returns early once ctx is done.
+func (s *Server) GetBytesToSignCtx(ctx context.Context, signer TxSigner, accNumber, sequence uint64, msgList []sdk.Msg, opts ...TxOption) (sdkClient.TxBuilder, []byte, error) {
+	txOpts := s.newTxOptions(opts)
+	txOpts.dropSelfFeePayer(signer)
+	if txOpts.feePayer != nil {
+		if err := s.KeepGrpcConnCtx(ctx); err != nil {
+			s.log().Error("error when keep grpc conn", "endpoint", s.endPoint, "err", err)
+			return nil, nil, err
+		}
+		conn := s.accountConn(txOpts.feePayer.signer.Address().String())
+		if err := s.resolveFeePayer(ctx, conn, txOpts, nil, true); err != nil {
+			return nil, nil, err
+		}
 	}
-	if resp.Code != 0 {
-		log.Printf("resp code not zero, log: %v\n", resp.RawLog)
-		return "", fmt.Errorf(resp.RawLog)
+
+	quote, err := s.feeQuote(ctx, txOpts)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return resp.TxHash, nil
+	return s.getBytesToSign(ctx, signer, accNumber, sequence, msgList, s.gas().limit, quote, txOpts)
 }
 
-// waitTxPacked waits for a transaction to be packed into a block.
+// getBytesToSign generates the bytes to sign for a transaction with the given gas limit.
 //
-// @param txHash the hash of the transaction to wait for
-func (s *Server) waitTxPacked(txHash string) {
-	conn, err := grpc.Dial(s.EndPoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// @param ctx the context controlling cancellation
+// @param signer the TxSigner used to sign the transaction
+// @param accNumber the account number of the signer
+// @param sequence the sequence number of the signer
+// @param msgList list of messages to include in the transaction
+// @param gasLimit the gas limit of the transaction, the fee is derived from it
+// @param quote the gas price of the transaction
+// @param o the options of the transaction
+// @return the transaction builder, bytes to sign, or an error if generation fails
+func (s *Server) getBytesToSign(ctx context.Context, signer TxSigner, accNumber, sequence uint64, msgList []sdk.Msg, gasLimit uint64, quote FeeQuote, o *txOptions) (sdkClient.TxBuilder, []byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	txBuilder, err := s.newTxBuilder(signer, sequence, msgList, gasLimit, quote, o)
 	if err != nil {
-		log.Printf("error when new grpc client: %s\n", err.Error())
-		return
+		return nil, nil, err
 	}
-	defer conn.Close()
 
-	txClient := sdkTx.NewServiceClient(conn)
-	defer conn.Close()
-	defer time.Sleep(500 * time.Millisecond)
+	bytesToSign, err := s.signBytes(txBuilder, signerAccount{signer: signer, accNumber: accNumber, sequence: sequence}, o)
+	if err != nil {
+		s.log().Error("error when get wait sign tx", "err", err)
+		return nil, nil, err
+	}
 
-	for i := 0; i < 10; i++ {
-		resp, err := txClient.GetTx(context.Background(), &sdkTx.GetTxRequest{Hash: txHash})
-		if err != nil {
-			if strings.Index(err.Error(), "tx not found") >= 0 {
-				log.Printf("wait tx %v packed", txHash)
-				<-time.NewTimer(time.Second).C
-				log.Printf("wait finish, try again")
-				continue
-			}
+	return txBuilder, bytesToSign, nil
+}
 
-			log.Printf("error when get tx: %v, err: %v", txHash, err.Error())
-			return
-		}
+// signBytes returns the bytes account signs for the transaction.
+//
+// @param txBuilder the transaction
+// @param account the signer with its account number and sequence
+// @param o the options of the transaction; in SignModeEIP712 the bytes are the EIP-712 hash
+// @return the bytes to sign, or an error if they can't be computed
+func (s *Server) signBytes(txBuilder sdkClient.TxBuilder, account signerAccount, o *txOptions) ([]byte, error) {
+	signerData := authSigning.SignerData{
+		ChainID:       s.chainID,
+		AccountNumber: account.accNumber,
+		Sequence:      account.sequence,
+		PubKey:        account.signer.PubKey(),
+		Address:       account.signer.Address().String(),
+	}
 
-		if resp != nil && resp.TxResponse != nil && resp.TxResponse.Height != 0 {
-			break
-		}
+	if o.signMode == SignModeEIP712 {
+		return eip712SignHash(txBuilder, signerData)
 	}
+
+	return txConfig.SignModeHandler().GetSignBytes(signMode, signerData, txBuilder.GetTx())
 }
 
-// GetBytesToSign generates the bytes to sign for a transaction.
+// newTxBuilder creates a transaction carrying msgList, its fee and empty signatures of signer
+// and of the fee payer of o, if any.
 //
-// @param signer the Signer instance used to sign the transaction
-// @param accNumber the account number of the signer
+// @param signer the TxSigner that will sign the transaction
 // @param sequence the sequence number of the signer
 // @param msgList list of messages to include in the transaction
-// @return the transaction builder, bytes to sign, or an error if generation fails
-func (s *Server) GetBytesToSign(signer Signer, accNumber, sequence uint64, msgList []sdk.Msg) (sdkClient.TxBuilder, []byte, error) {
+// @param gasLimit the gas limit of the transaction, the fee is derived from it
+// @param quote the gas price of the transaction; a priority tip makes it a dynamic fee transaction
+// @param o the options of the transaction
+// @return the transaction builder, or an error if the messages are invalid
+func (s *Server) newTxBuilder(signer TxSigner, sequence uint64, msgList []sdk.Msg, gasLimit uint64, quote FeeQuote, o *txOptions) (sdkClient.TxBuilder, error) {
 	for _, msg := range msgList {
 		if err := msg.ValidateBasic(); err != nil {
-			log.Printf("error when validate basic for msg: %v, err: %v\n", msg, err.Error())
-			return nil, nil, err
+			s.log().Error("error when validate basic for msg", "msg", msg, "err", err)
+			return nil, err
 		}
 	}
 
 	txBuilder := txConfig.NewTxBuilder()
 	err := txBuilder.SetMsgs(msgList...)
 	if err != nil {
-		log.Printf("error when set msg, err: %s", err.Error())
-		return nil, nil, err
+		s.log().Error("error when set msg", "err", err)
+		return nil, err
 	}
 
-	fees := make(sdk.Coins, 1)
-	fees[0] = sdk.NewCoin(
-		s.GasCoin,
-		sdk.NewDec(s.GasPrice*int64(s.GasLimit)).Ceil().RoundInt(),
-	)
-
-	txBuilder.SetFeeAmount(fees)
-	txBuilder.SetGasLimit(s.GasLimit)
-	txBuilder.SetFeePayer(signer.CosmosAddr)
-
-	signerData := authSigning.SignerData{
-		ChainID:       s.ChainID,
-		AccountNumber: accNumber,
-		Sequence:      sequence,
-		PubKey:        signer.publicKey,
-		Address:       signer.CosmosAddr.String(),
+	accounts := []signerAccount{{signer: signer, sequence: sequence}}
+	if o.feePayer != nil {
+		accounts = append(accounts, *o.feePayer)
 	}
+	feePayer := accounts[len(accounts)-1].signer.Address()
 
-	sigData := signing.SingleSignatureData{
-		SignMode:  signMode,
-		Signature: nil,
+	txBuilder.SetFeeAmount(s.feeFor(gasLimit, quote))
+	txBuilder.SetGasLimit(gasLimit)
+	txBuilder.SetFeePayer(feePayer)
+	if !o.feeGranter.Empty() {
+		txBuilder.SetFeeGranter(o.feeGranter)
 	}
-	sig := signing.SignatureV2{
-		PubKey:   signer.publicKey,
-		Data:     &sigData,
-		Sequence: sequence,
-	}
-	if err := txBuilder.SetSignatures(sig); err != nil {
-		log.Printf("error when set signatures, err: %s\n", err.Error())
-		return nil, nil, err
+
+	if o.signMode == SignModeEIP712 {
+		if quote.PriorityTip != nil {
+			return nil, fmt.Errorf("EIP-712 transactions can't carry a priority tip")
+		}
+		if err := setWeb3Extension(txBuilder, s.chainID, feePayer, nil); err != nil {
+			s.log().Error("error when set web3 extension", "err", err)
+			return nil, err
+		}
+	} else if quote.PriorityTip != nil {
+		option, err := codecTypes.NewAnyWithValue(&cysicTypes.ExtensionOptionDynamicFeeTx{MaxPriorityPrice: *quote.PriorityTip})
+		if err != nil {
+			s.log().Error("error when pack dynamic fee option", "err", err)
+			return nil, err
+		}
+		extBuilder, ok := txBuilder.(authTx.ExtensionOptionsTxBuilder)
+		if !ok {
+			return nil, fmt.Errorf("tx builder does not support extension options")
+		}
+		extBuilder.SetExtensionOptions(option)
 	}
 
-	bytesToSign, err := txConfig.SignModeHandler().GetSignBytes(signMode, signerData, txBuilder.GetTx())
-	if err != nil {
-		log.Printf("error when get wait sign tx, err: %v\n", err.Error())
-		return nil, nil, err
+	sigs := make([]signing.SignatureV2, 0, len(accounts))
+	for _, account := range accounts {
+		sigData := signing.SingleSignatureData{
+			SignMode:  o.signMode.protoSignMode(),
+			Signature: nil,
+		}
+		sigs = append(sigs, signing.SignatureV2{
+			PubKey:   account.signer.PubKey(),
+			Data:     &sigData,
+			Sequence: account.sequence,
+		})
+	}
+	if err := txBuilder.SetSignatures(sigs...); err != nil {
+		s.log().Error("error when set signatures", "err", err)
+		return nil, err
 	}
 
-	return txBuilder, bytesToSign, nil
+	return txBuilder, nil
 }
 
 // broadcastMsg broadcasts a single message as a transaction.
 //
-// @param signer the Signer instance used to sign the transaction
+// @param ctx the context controlling cancellation and deadline
+// @param signer the TxSigner used to sign the transaction
 // @param msg the message to broadcast
 // @return the transaction hash as a string, or an error if broadcasting fails
-func (s *Server) broadcastMsg(signer Signer, msg sdk.Msg) (string, error) {
-	if err := s.KeepGrpcConn(); err != nil {
-		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+func (s *Server) broadcastMsg(ctx context.Context, signer TxSigner, msg sdk.Msg) (string, error) {
+	if err := s.KeepGrpcConnCtx(ctx); err != nil {
+		s.log().Error("error when keep grpc conn", "endpoint", s.endPoint, "err", err)
 		return "", err
 	}
 
@@ -294,5 +673,5 @@ func (s *Server) broadcastMsg(signer Signer, msg sdk.Msg) (string, error) {
 		return "", err
 	}
 
-	return s.buildAndBroadcastCosmosTx(signer, []sdk.Msg{msg})
+	return s.buildAndBroadcastCosmosTx(ctx, signer, []sdk.Msg{msg})
 }
@@ -0,0 +1,145 @@
+package gosdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cysic-tech/gosdk/crypto/ethsecp256k1"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	"github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth/migrations/legacytx"
+)
+
+const (
+	// adr036MsgType is the amino type of the message wrapping ADR-036 arbitrary data.
+	adr036MsgType = "sign/MsgSignData"
+	// adr036EthermintPubKeyName is the amino type Keplr gives eth_secp256k1 keys of Ethermint chains.
+	adr036EthermintPubKeyName = "ethermint/PubKeyEthSecp256k1"
+)
+
+type adr036SignDoc struct {
+	AccountNumber string          `json:"account_number"`
+	ChainID       string          `json:"chain_id"`
+	Fee           adr036Fee       `json:"fee"`
+	Memo          string          `json:"memo"`
+	Msgs          []adr036SignMsg `json:"msgs"`
+	Sequence      string          `json:"sequence"`
+}
+
+type adr036Signature struct {
+	PubKey struct {
+		Type  string `json:"type"`
+		Value []byte `json:"value"`
+	} `json:"pub_key"`
+	Signature []byte `json:"signature"`
+}
+
+type adr036Fee struct {
+	Amount []sdk.Coin `json:"amount"`
+	Gas    string     `json:"gas"`
+}
+
+type adr036SignMsg struct {
+	Type  string          `json:"type"`
+	Value adr036SignValue `json:"value"`
+}
+
+type adr036SignValue struct {
+	Data   []byte `json:"data"`
+	Signer string `json:"signer"`
+}
+
+// ADR036SignBytes returns the bytes signed for data under ADR-036: the amino JSON sign doc of a
+// single sign/MsgSignData with an empty chain ID, zero account number and sequence, and no fee,
+// as Keplr's signArbitrary produces it.
+//
+// @param signer the bech32 address of the signer
+// @param data the arbitrary data
+// @return the sorted JSON sign doc, or an error if the address is invalid
+func ADR036SignBytes(signer string, data []byte) ([]byte, error) {
+	if _, err := sdk.AccAddressFromBech32(signer); err != nil {
+		return nil, fmt.Errorf("invalid address '%s': %w", signer, err)
+	}
+
+	bz, err := json.Marshal(adr036SignDoc{
+		AccountNumber: "0",
+		Fee:           adr036Fee{Amount: []sdk.Coin{}, Gas: "0"},
+		Msgs:          []adr036SignMsg{{Type: adr036MsgType, Value: adr036SignValue{Data: data, Signer: signer}}},
+		Sequence:      "0",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sdk.SortJSON(bz)
+}
+
+// SignADR036 signs arbitrary data under ADR-036 to prove ownership of the Cosmos address of the Signer.
+//
+// @param s the Signer instance
+// @param data the data to sign, e.g. a login challenge
+// @return the amino JSON StdSignature {"pub_key":{"type":...,"value":...},"signature":...}, or an error if signing fails
+func (s *Signer) SignADR036(data []byte) ([]byte, error) {
+	signBytes, err := ADR036SignBytes(s.CosmosAddr.String(), data)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := s.SignBytes(context.Background(), signBytes)
+	if err != nil {
+		logger().Error("error when sign adr036 data", "err", err)
+		return nil, err
+	}
+
+	return legacyAmino.MarshalJSON(legacytx.StdSignature{PubKey: s.publicKey, Signature: sig}) //nolint: staticcheck
+}
+
+// VerifyADR036Signature verifies an ADR-036 signature of data by a Cosmos address, as returned by
+// SignADR036 or Keplr's signArbitrary. eth_secp256k1 (under the cysicmint or ethermint amino
+// name) and secp256k1 keys are supported.
+//
+// @param address the bech32 address that signed the data
+// @param data the data that was signed
+// @param sig the amino JSON StdSignature carrying the public key and the signature
+// @return true if the signature is valid, false otherwise
+func VerifyADR036Signature(address string, data []byte, sig []byte) bool {
+	var stdSig adr036Signature
+	if err := json.Unmarshal(sig, &stdSig); err != nil {
+		logger().Error("error when unmarshal adr036 signature", "err", err)
+		return false
+	}
+	if len(stdSig.PubKey.Value) != ethsecp256k1.PubKeySize {
+		logger().Error("invalid adr036 public key size", "size", len(stdSig.PubKey.Value))
+		return false
+	}
+
+	var pubKey types.PubKey
+	switch stdSig.PubKey.Type {
+	case ethsecp256k1.PubKeyName, adr036EthermintPubKeyName:
+		pubKey = &ethsecp256k1.PubKey{Key: stdSig.PubKey.Value}
+	case secp256k1.PubKeyName:
+		pubKey = &secp256k1.PubKey{Key: stdSig.PubKey.Value}
+	default:
+		logger().Error("unsupported adr036 public key type", "type", stdSig.PubKey.Type)
+		return false
+	}
+
+	accAddr, err := sdk.AccAddressFromBech32(address)
+	if err != nil {
+		logger().Error("error when convert addr", "address", address, "err", err)
+		return false
+	}
+	if !sdk.AccAddress(pubKey.Address()).Equals(accAddr) {
+		return false
+	}
+
+	signBytes, err := ADR036SignBytes(address, data)
+	if err != nil {
+		return false
+	}
+
+	return pubKey.VerifySignature(signBytes, stdSig.Signature)
+}
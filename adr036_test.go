@@ -0,0 +1,136 @@
+package gosdk
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/cysic-tech/gosdk/crypto/ethsecp256k1"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+)
+
+// A signArbitrary result in the format Keplr returns for eth_secp256k1 keys of Ethermint
+// chains: the ethermint amino key name and a 64-byte [R || S] signature of the Keccak-256 hash
+// of the sign doc, for the key cliTxKey and the data adr036VectorData.
+const (
+	adr036VectorData    = "Login to cysic"
+	adr036VectorAddress = "cysic1rfjz7r3u8t65teavh5utquj3kwvsj983qt59nr"
+	adr036VectorSignDoc = `{"account_number":"0","chain_id":"","fee":{"amount":[],"gas":"0"},"memo":"","msgs":[{"type":"sign/MsgSignData","value":{"data":"TG9naW4gdG8gY3lzaWM=","signer":"cysic1rfjz7r3u8t65teavh5utquj3kwvsj983qt59nr"}}],"sequence":"0"}`
+	adr036VectorPubKey  = "AxuExVZ7EmRAmV0+1aq6BWXXHhg0YEgZ/5wX9enV3QeP"
+	adr036VectorSig     = "K2tnH3v9dbT9D5U4Tbf9AC11KIhvWE6OOecGuFMqsM5CQkgHKIbFCj9INViZOv8bHZNkNjzXVhcTNkF8fMKBaQ=="
+)
+
+// adr036SignatureJSON encodes a StdSignature the way Keplr's signArbitrary returns it.
+func adr036SignatureJSON(pubKeyType string, pubKey string, sig string) []byte {
+	return []byte(fmt.Sprintf(`{"pub_key":{"type":%q,"value":%q},"signature":%q}`, pubKeyType, pubKey, sig))
+}
+
+func TestADR036RoundTrip(t *testing.T) {
+	cosmos, err := NewSignerWithPrivateKeyAndAlgo(secp256k1.GenPrivKey().Bytes(), "secp256k1")
+	if err != nil {
+		t.Fatalf("new secp256k1 signer: %v", err)
+	}
+
+	for name, tc := range map[string]struct {
+		signer     *Signer
+		pubKeyType string
+	}{
+		"eth_secp256k1": {newTestSigner(t), ethsecp256k1.PubKeyName},
+		"secp256k1":     {cosmos, secp256k1.PubKeyName},
+	} {
+		t.Run(name, func(t *testing.T) {
+			signer := tc.signer
+			data := []byte("nonce 42")
+			sig, err := signer.SignADR036(data)
+			if err != nil {
+				t.Fatalf("sign: %v", err)
+			}
+
+			var stdSig adr036Signature
+			if err := json.Unmarshal(sig, &stdSig); err != nil {
+				t.Fatalf("decode signature: %v", err)
+			}
+			if stdSig.PubKey.Type != tc.pubKeyType {
+				t.Errorf("pub key type = %v, want %v", stdSig.PubKey.Type, tc.pubKeyType)
+			}
+
+			address := signer.Address().String()
+			if !VerifyADR036Signature(address, data, sig) {
+				t.Errorf("signature of %v does not verify", address)
+			}
+			if VerifyADR036Signature(newTestSigner(t).Address().String(), data, sig) {
+				t.Error("signature verifies for another address")
+			}
+			if VerifyADR036Signature(address, []byte("nonce 43"), sig) {
+				t.Error("signature verifies for other data")
+			}
+		})
+	}
+}
+
+func TestADR036KeplrVector(t *testing.T) {
+	signDoc, err := ADR036SignBytes(adr036VectorAddress, []byte(adr036VectorData))
+	if err != nil {
+		t.Fatalf("sign bytes: %v", err)
+	}
+	if string(signDoc) != adr036VectorSignDoc {
+		t.Errorf("sign doc = %s, want %s", signDoc, adr036VectorSignDoc)
+	}
+
+	sig := adr036SignatureJSON(adr036EthermintPubKeyName, adr036VectorPubKey, adr036VectorSig)
+	if !VerifyADR036Signature(adr036VectorAddress, []byte(adr036VectorData), sig) {
+		t.Errorf("the Keplr signature does not verify")
+	}
+
+	// the key the vector was signed with produces the same signature, with a recovery byte
+	signer := newOfflineSigner(t, cliTxKey)
+	signed, err := signer.SignADR036([]byte(adr036VectorData))
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	var stdSig adr036Signature
+	if err := json.Unmarshal(signed, &stdSig); err != nil {
+		t.Fatalf("decode signature: %v", err)
+	}
+	if got := base64.StdEncoding.EncodeToString(stdSig.PubKey.Value); got != adr036VectorPubKey {
+		t.Errorf("pub key = %v, want %v", got, adr036VectorPubKey)
+	}
+	if got := base64.StdEncoding.EncodeToString(stdSig.Signature[:64]); len(stdSig.Signature) != 65 || got != adr036VectorSig {
+		t.Errorf("signature = %x, want %v and a recovery byte", stdSig.Signature, adr036VectorSig)
+	}
+}
+
+func TestVerifyADR036SignatureRejects(t *testing.T) {
+	data := []byte(adr036VectorData)
+	shortKey := base64.StdEncoding.EncodeToString(make([]byte, 32))
+
+	for name, tc := range map[string]struct {
+		address string
+		data    []byte
+		sig     []byte
+	}{
+		"wrong address":   {newTestSigner(t).Address().String(), data, adr036SignatureJSON(adr036EthermintPubKeyName, adr036VectorPubKey, adr036VectorSig)},
+		"invalid address": {"cosmos1invalid", data, adr036SignatureJSON(adr036EthermintPubKeyName, adr036VectorPubKey, adr036VectorSig)},
+		"changed data":    {adr036VectorAddress, []byte("Login to cysic!"), adr036SignatureJSON(adr036EthermintPubKeyName, adr036VectorPubKey, adr036VectorSig)},
+		// a secp256k1 key hashes with SHA-256 and has another address
+		"secp256k1 type": {adr036VectorAddress, data, adr036SignatureJSON(secp256k1.PubKeyName, adr036VectorPubKey, adr036VectorSig)},
+		"unknown type":   {adr036VectorAddress, data, adr036SignatureJSON("tendermint/PubKeyEd25519", adr036VectorPubKey, adr036VectorSig)},
+		"short key":      {adr036VectorAddress, data, adr036SignatureJSON(adr036EthermintPubKeyName, shortKey, adr036VectorSig)},
+		"short sig":      {adr036VectorAddress, data, adr036SignatureJSON(adr036EthermintPubKeyName, adr036VectorPubKey, adr036VectorSig[:40])},
+		"malformed JSON": {adr036VectorAddress, data, []byte(`{"pub_key":`)},
+		"not base64":     {adr036VectorAddress, data, adr036SignatureJSON(adr036EthermintPubKeyName, "not base64!", adr036VectorSig)},
+	} {
+		if VerifyADR036Signature(tc.address, tc.data, tc.sig) {
+			t.Errorf("%v: signature verifies", name)
+		}
+	}
+}
+
+func TestSignADR036WithoutKey(t *testing.T) {
+	signer := &Signer{CosmosAddr: newTestSigner(t).Address()}
+	if _, err := signer.SignADR036([]byte("data")); err == nil {
+		t.Error("sign without a private key: got no error")
+	}
+}
@@ -0,0 +1,296 @@
+package gosdk
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+// GrantGeneric lets grantee execute any message of type msgTypeURL on behalf of the signer.
+//
+// @param signer the TxSigner of the granter
+// @param grantee the address of the grantee
+// @param msgTypeURL the type URL of the message, e.g. "/cosmos.distribution.v1beta1.MsgWithdrawDelegatorReward"
+// @param expiration the time the grant expires, never if nil
+// @return the transaction hash as a string, or an error if the grant fails
+func (s *Server) GrantGeneric(signer TxSigner, grantee string, msgTypeURL string, expiration *time.Time) (string, error) {
+	return s.GrantGenericCtx(context.Background(), signer, grantee, msgTypeURL, expiration)
+}
+
+// GrantGenericCtx is like GrantGeneric but honours ctx for cancellation and deadlines.
+func (s *Server) GrantGenericCtx(ctx context.Context, signer TxSigner, grantee string, msgTypeURL string, expiration *time.Time) (string, error) {
+	return s.GrantAuthorizationCtx(ctx, signer, grantee, authz.NewGenericAuthorization(msgTypeURL), expiration)
+}
+
+// GrantSend lets grantee send up to spendLimit from the signer's balance.
+//
+// @param signer the TxSigner of the granter
+// @param grantee the address of the grantee
+// @param spendLimit the total amount grantee may send
+// @param expiration the time the grant expires, never if nil
+// @return the transaction hash as a string, or an error if the grant fails
+func (s *Server) GrantSend(signer TxSigner, grantee string, spendLimit sdk.Coins, expiration *time.Time) (string, error) {
+	return s.GrantSendCtx(context.Background(), signer, grantee, spendLimit, expiration)
+}
+
+// GrantSendCtx is like GrantSend but honours ctx for cancellation and deadlines.
+func (s *Server) GrantSendCtx(ctx context.Context, signer TxSigner, grantee string, spendLimit sdk.Coins, expiration *time.Time) (string, error) {
+	return s.GrantAuthorizationCtx(ctx, signer, grantee, banktypes.NewSendAuthorization(spendLimit), expiration)
+}
+
+// GrantStake lets grantee delegate, undelegate or redelegate the signer's tokens, depending on
+// authzType, with either allowValidators or denyValidators restricting the validators.
+//
+// @param signer the TxSigner of the granter
+// @param grantee the address of the grantee
+// @param authzType the staking message the grant covers
+// @param allowValidators the only validators grantee may use
+// @param denyValidators the validators grantee may not use
+// @param maxTokens the total amount grantee may stake, unlimited if nil
+// @param expiration the time the grant expires, never if nil
+// @return the transaction hash as a string, or an error if the grant fails
+func (s *Server) GrantStake(signer TxSigner, grantee string, authzType stakingtypes.AuthorizationType, allowValidators []string, denyValidators []string, maxTokens *sdk.Coin, expiration *time.Time) (string, error) {
+	return s.GrantStakeCtx(context.Background(), signer, grantee, authzType, allowValidators, denyValidators, maxTokens, expiration)
+}
+
+// GrantStakeCtx is like GrantStake but honours ctx for cancellation and deadlines.
+func (s *Server) GrantStakeCtx(ctx context.Context, signer TxSigner, grantee string, authzType stakingtypes.AuthorizationType, allowValidators []string, denyValidators []string, maxTokens *sdk.Coin, expiration *time.Time) (string, error) {
+	allowed, err := toValAddresses(allowValidators)
+	if err != nil {
+		return "", err
+	}
+	denied, err := toValAddresses(denyValidators)
+	if err != nil {
+		return "", err
+	}
+
+	authorization, err := stakingtypes.NewStakeAuthorization(allowed, denied, authzType, maxTokens)
+	if err != nil {
+		s.log().Error("error when create stake authorization", "err", err)
+		return "", err
+	}
+
+	return s.GrantAuthorizationCtx(ctx, signer, grantee, authorization, expiration)
+}
+
+// GrantAuthorization grants grantee any authorization on behalf of the signer.
+//
+// @param signer the TxSigner of the granter
+// @param grantee the address of the grantee
+// @param authorization the authorization
+// @param expiration the time the grant expires, never if nil
+// @return the transaction hash as a string, or an error if the grant fails
+func (s *Server) GrantAuthorization(signer TxSigner, grantee string, authorization authz.Authorization, expiration *time.Time) (string, error) {
+	return s.GrantAuthorizationCtx(context.Background(), signer, grantee, authorization, expiration)
+}
+
+// GrantAuthorizationCtx is like GrantAuthorization but honours ctx for cancellation and deadlines.
+func (s *Server) GrantAuthorizationCtx(ctx context.Context, signer TxSigner, grantee string, authorization authz.Authorization, expiration *time.Time) (string, error) {
+	granteeAddr, err := toAccAddress(grantee)
+	if err != nil {
+		s.log().Error("error when convert addr to cosmosAddr", "grantee", grantee, "err", err)
+		return "", err
+	}
+
+	msg, err := authz.NewMsgGrant(signer.Address(), granteeAddr, authorization, expiration)
+	if err != nil {
+		s.log().Error("error when create grant msg", "err", err)
+		return "", err
+	}
+
+	return s.buildAndBroadcastCosmosTx(ctx, signer, []sdk.Msg{msg})
+}
+
+// Revoke revokes the grant of msgTypeURL the signer gave to grantee.
+//
+// @param signer the TxSigner of the granter
+// @param grantee the address of the grantee
+// @param msgTypeURL the type URL of the message the grant covers
+// @return the transaction hash as a string, or an error if the revocation fails
+func (s *Server) Revoke(signer TxSigner, grantee string, msgTypeURL string) (string, error) {
+	return s.RevokeCtx(context.Background(), signer, grantee, msgTypeURL)
+}
+
+// RevokeCtx is like Revoke but honours ctx for cancellation and deadlines.
+func (s *Server) RevokeCtx(ctx context.Context, signer TxSigner, grantee string, msgTypeURL string) (string, error) {
+	granteeAddr, err := toAccAddress(grantee)
+	if err != nil {
+		s.log().Error("error when convert addr to cosmosAddr", "grantee", grantee, "err", err)
+		return "", err
+	}
+
+	msg := authz.NewMsgRevoke(signer.Address(), granteeAddr, msgTypeURL)
+	return s.buildAndBroadcastCosmosTx(ctx, signer, []sdk.Msg{&msg})
+}
+
+// Exec executes msgList on behalf of granter, which granted the signer the authorizations
+// they need. The messages are built as if granter sent them, e.g. with NewSendMsg,
+// NewWithdrawDelegatorRewardMsg or NewDelegateCGTMsg.
+//
+// @param signer the TxSigner of the grantee
+// @param granter the address of the granter
+// @param msgList the messages to execute, all signed by granter
+// @return the transaction hash as a string, or an error if the execution fails
+func (s *Server) Exec(signer TxSigner, granter string, msgList ...sdk.Msg) (string, error) {
+	return s.ExecCtx(context.Background(), signer, granter, msgList...)
+}
+
+// ExecCtx is like Exec but honours ctx for cancellation and deadlines.
+func (s *Server) ExecCtx(ctx context.Context, signer TxSigner, granter string, msgList ...sdk.Msg) (string, error) {
+	if len(msgList) == 0 {
+		return "", fmt.Errorf("msg list is empty")
+	}
+
+	granterAddr, err := toAccAddress(granter)
+	if err != nil {
+		s.log().Error("error when convert addr to cosmosAddr", "granter", granter, "err", err)
+		return "", err
+	}
+	for _, msg := range msgList {
+		for _, msgSigner := range msg.GetSigners() {
+			if !msgSigner.Equals(granterAddr) {
+				return "", fmt.Errorf("msg %v is signed by %v, not by granter %v", sdk.MsgTypeURL(msg), msgSigner.String(), granterAddr.String())
+			}
+		}
+	}
+
+	msg := authz.NewMsgExec(signer.Address(), msgList)
+	return s.buildAndBroadcastCosmosTx(ctx, signer, []sdk.Msg{&msg})
+}
+
+// QueryGrants retrieves the grants granter gave grantee.
+//
+// @param granter the address of the granter
+// @param grantee the address of the grantee
+// @param msgTypeURL the type URL of the message the grants cover, all grants if empty
+// @return a list of grants, or an error if the query fails
+func (s *Server) QueryGrants(granter string, grantee string, msgTypeURL string) ([]*authz.Grant, error) {
+	return s.QueryGrantsCtx(context.Background(), granter, grantee, msgTypeURL)
+}
+
+// QueryGrantsCtx is like QueryGrants but honours ctx for cancellation and deadlines.
+func (s *Server) QueryGrantsCtx(ctx context.Context, granter string, grantee string, msgTypeURL string) ([]*authz.Grant, error) {
+	granterAddr, err := ConvertToCysicAddress(granter)
+	if err != nil {
+		s.log().Error("error when convert addr to cosmosAddr", "granter", granter, "err", err)
+		return nil, err
+	}
+	granteeAddr, err := ConvertToCysicAddress(grantee)
+	if err != nil {
+		s.log().Error("error when convert addr to cosmosAddr", "grantee", grantee, "err", err)
+		return nil, err
+	}
+
+	if err := s.KeepGrpcConnCtx(ctx); err != nil {
+		s.log().Error("error when keep grpc conn", "endpoint", s.endPoint, "err", err)
+		return nil, err
+	}
+
+	client := authz.NewQueryClient(s.conn())
+	req := &authz.QueryGrantsRequest{Granter: granterAddr, Grantee: granteeAddr, MsgTypeUrl: msgTypeURL}
+	resp, err := client.Grants(ctx, req)
+	if err != nil {
+		s.log().Error("could not query grants", "err", err)
+		return nil, wrapCtxErr(ctx, err)
+	}
+
+	return resp.Grants, nil
+}
+
+// QueryGranterGrants retrieves the grants given by granter with pagination.
+//
+// @param granter the address of the granter
+// @param offset the offset for pagination
+// @param pageSize the page size for pagination
+// @return a list of grants, the total count, or an error if the query fails
+func (s *Server) QueryGranterGrants(granter string, offset uint64, pageSize uint64) ([]*authz.GrantAuthorization, uint64, error) {
+	return s.QueryGranterGrantsCtx(context.Background(), granter, offset, pageSize)
+}
+
+// QueryGranterGrantsCtx is like QueryGranterGrants but honours ctx for cancellation and deadlines.
+func (s *Server) QueryGranterGrantsCtx(ctx context.Context, granter string, offset uint64, pageSize uint64) ([]*authz.GrantAuthorization, uint64, error) {
+	granterAddr, err := ConvertToCysicAddress(granter)
+	if err != nil {
+		s.log().Error("error when convert addr to cosmosAddr", "granter", granter, "err", err)
+		return nil, 0, err
+	}
+
+	if err := s.KeepGrpcConnCtx(ctx); err != nil {
+		s.log().Error("error when keep grpc conn", "endpoint", s.endPoint, "err", err)
+		return nil, 0, err
+	}
+
+	client := authz.NewQueryClient(s.conn())
+	req := &authz.QueryGranterGrantsRequest{
+		Granter:    granterAddr,
+		Pagination: &query.PageRequest{Offset: offset, Limit: pageSize, CountTotal: true},
+	}
+	resp, err := client.GranterGrants(ctx, req)
+	if err != nil {
+		s.log().Error("could not query granter grants", "err", err)
+		return nil, 0, wrapCtxErr(ctx, err)
+	}
+
+	return resp.Grants, resp.Pagination.GetTotal(), nil
+}
+
+// QueryGranteeGrants retrieves the grants given to grantee with pagination.
+//
+// @param grantee the address of the grantee
+// @param offset the offset for pagination
+// @param pageSize the page size for pagination
+// @return a list of grants, the total count, or an error if the query fails
+func (s *Server) QueryGranteeGrants(grantee string, offset uint64, pageSize uint64) ([]*authz.GrantAuthorization, uint64, error) {
+	return s.QueryGranteeGrantsCtx(context.Background(), grantee, offset, pageSize)
+}
+
+// QueryGranteeGrantsCtx is like QueryGranteeGrants but honours ctx for cancellation and deadlines.
+func (s *Server) QueryGranteeGrantsCtx(ctx context.Context, grantee string, offset uint64, pageSize uint64) ([]*authz.GrantAuthorization, uint64, error) {
+	granteeAddr, err := ConvertToCysicAddress(grantee)
+	if err != nil {
+		s.log().Error("error when convert addr to cosmosAddr", "grantee", grantee, "err", err)
+		return nil, 0, err
+	}
+
+	if err := s.KeepGrpcConnCtx(ctx); err != nil {
+		s.log().Error("error when keep grpc conn", "endpoint", s.endPoint, "err", err)
+		return nil, 0, err
+	}
+
+	client := authz.NewQueryClient(s.conn())
+	req := &authz.QueryGranteeGrantsRequest{
+		Grantee:    granteeAddr,
+		Pagination: &query.PageRequest{Offset: offset, Limit: pageSize, CountTotal: true},
+	}
+	resp, err := client.GranteeGrants(ctx, req)
+	if err != nil {
+		s.log().Error("could not query grantee grants", "err", err)
+		return nil, 0, wrapCtxErr(ctx, err)
+	}
+
+	return resp.Grants, resp.Pagination.GetTotal(), nil
+}
+
+// toValAddresses parses bech32 validator addresses.
+//
+// @param addresses the validator addresses
+// @return the parsed addresses, or an error if one is invalid
+func toValAddresses(addresses []string) ([]sdk.ValAddress, error) {
+	result := make([]sdk.ValAddress, 0, len(addresses))
+	for _, address := range addresses {
+		valAddr, err := sdk.ValAddressFromBech32(address)
+		if err != nil {
+			logger().Error("error when parse validator addr", "address", address, "err", err)
+			return nil, err
+		}
+		result = append(result, valAddr)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,144 @@
+package gosdk
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	bankTypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+func TestExecChecksSigners(t *testing.T) {
+	chain := newMockChain()
+	node := chain.serve(t)
+	s := newTestServer(t, node)
+
+	grantee, granter, other := newTestSigner(t), newTestSigner(t), newTestSigner(t)
+	recipient := newTestSigner(t).Address().String()
+	chain.fund(grantee.Address(), testCoins(1_000_000))
+
+	fromGranter, err := NewSendMsg(granter.Address().String(), recipient, CYSToken, sdkmath.NewInt(1))
+	if err != nil {
+		t.Fatalf("new send msg: %v", err)
+	}
+	fromOther, err := NewSendMsg(other.Address().String(), recipient, CYSToken, sdkmath.NewInt(1))
+	if err != nil {
+		t.Fatalf("new send msg: %v", err)
+	}
+
+	if _, err := s.Exec(grantee, granter.Address().String(), fromGranter, fromOther); err == nil || !strings.Contains(err.Error(), "not by granter") {
+		t.Errorf("msg of another account: got %v, want a signer error", err)
+	}
+	if _, err := s.Exec(grantee, granter.Address().String()); err == nil {
+		t.Error("no msgs: got no error")
+	}
+	if n := node.callCount(broadcastMethod); n != 0 {
+		t.Errorf("%d broadcasts, want none", n)
+	}
+
+	// the granter may be given by its hex address
+	txHash, err := s.Exec(grantee, granter.EthAddr.Hex(), fromGranter)
+	if err != nil {
+		t.Fatalf("exec: %v", err)
+	}
+	tx := chain.includedTx(t, txHash)
+	if signers := tx.GetSigners(); len(signers) != 1 || !signers[0].Equals(grantee.Address()) {
+		t.Errorf("tx signers = %v, want the grantee %v", signers, grantee.Address())
+	}
+	exec, ok := tx.GetMsgs()[0].(*authz.MsgExec)
+	if !ok {
+		t.Fatalf("msg is a %T, want a MsgExec", tx.GetMsgs()[0])
+	}
+	msgs, err := exec.GetMessages()
+	if err != nil {
+		t.Fatalf("unpack exec msgs: %v", err)
+	}
+	if exec.Grantee != grantee.Address().String() || len(msgs) != 1 || !msgs[0].GetSigners()[0].Equals(granter.Address()) {
+		t.Errorf("exec = %+v, want the granter's send executed by the grantee", exec)
+	}
+}
+
+func TestGrantAuthorizations(t *testing.T) {
+	chain := newMockChain()
+	node := chain.serve(t)
+	s := newTestServer(t, node)
+
+	granter, grantee := newTestSigner(t), newTestSigner(t).Address()
+	chain.fund(granter.Address(), testCoins(1_000_000))
+	expiration := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+
+	granted := func(txHash string) authz.Authorization {
+		t.Helper()
+
+		msgs := chain.includedTx(t, txHash).GetMsgs()
+		msg, ok := msgs[0].(*authz.MsgGrant)
+		if len(msgs) != 1 || !ok {
+			t.Fatalf("msgs = %v, want one MsgGrant", msgs)
+		}
+		if msg.Granter != granter.Address().String() || msg.Grantee != grantee.String() {
+			t.Errorf("grant from %v to %v, want from %v to %v", msg.Granter, msg.Grantee, granter.Address(), grantee)
+		}
+		if msg.Grant.Expiration == nil || !msg.Grant.Expiration.Equal(expiration) {
+			t.Errorf("expiration = %v, want %v", msg.Grant.Expiration, expiration)
+		}
+		authorization, err := msg.GetAuthorization()
+		if err != nil {
+			t.Fatalf("unpack authorization: %v", err)
+		}
+		if err := authorization.ValidateBasic(); err != nil {
+			t.Errorf("invalid authorization: %v", err)
+		}
+		return authorization
+	}
+
+	spendLimit := testCoins(500)
+	txHash, err := s.GrantSend(granter, grantee.String(), spendLimit, &expiration)
+	if err != nil {
+		t.Fatalf("grant send: %v", err)
+	}
+	send, ok := granted(txHash).(*bankTypes.SendAuthorization)
+	if !ok || !send.SpendLimit.IsEqual(spendLimit) || send.MsgTypeURL() != sdk.MsgTypeURL(&bankTypes.MsgSend{}) {
+		t.Errorf("authorization = %+v, want a send authorization of %v", send, spendLimit)
+	}
+
+	validator := sdk.ValAddress(newTestSigner(t).Address())
+	maxTokens := sdk.NewCoin(CYSToken, sdkmath.NewInt(1_000))
+	txHash, err = s.GrantStake(granter, grantee.String(), stakingtypes.AuthorizationType_AUTHORIZATION_TYPE_DELEGATE, []string{validator.String()}, nil, &maxTokens, &expiration)
+	if err != nil {
+		t.Fatalf("grant stake: %v", err)
+	}
+	stake, ok := granted(txHash).(*stakingtypes.StakeAuthorization)
+	if !ok {
+		t.Fatalf("authorization is not a stake authorization")
+	}
+	if stake.AuthorizationType != stakingtypes.AuthorizationType_AUTHORIZATION_TYPE_DELEGATE || stake.MsgTypeURL() != sdk.MsgTypeURL(&stakingtypes.MsgDelegate{}) {
+		t.Errorf("stake authorization type = %v, want delegate", stake.AuthorizationType)
+	}
+	if allow := stake.GetAllowList(); allow == nil || len(allow.Address) != 1 || allow.Address[0] != validator.String() {
+		t.Errorf("allowed validators = %v, want %v", stake.GetAllowList(), validator)
+	}
+	if stake.MaxTokens == nil || !stake.MaxTokens.IsEqual(maxTokens) {
+		t.Errorf("max tokens = %v, want %v", stake.MaxTokens, maxTokens)
+	}
+
+	withdraw := "/cosmos.distribution.v1beta1.MsgWithdrawDelegatorReward"
+	txHash, err = s.GrantGeneric(granter, grantee.String(), withdraw, &expiration)
+	if err != nil {
+		t.Fatalf("grant generic: %v", err)
+	}
+	if generic, ok := granted(txHash).(*authz.GenericAuthorization); !ok || generic.Msg != withdraw {
+		t.Errorf("authorization = %+v, want a generic authorization of %v", generic, withdraw)
+	}
+
+	// a validator can't be both allowed and denied
+	if _, err := s.GrantStake(granter, grantee.String(), stakingtypes.AuthorizationType_AUTHORIZATION_TYPE_DELEGATE, []string{validator.String()}, []string{validator.String()}, nil, nil); err == nil {
+		t.Error("allow and deny lists: got no error")
+	}
+	if _, err := s.GrantStake(granter, grantee.String(), stakingtypes.AuthorizationType_AUTHORIZATION_TYPE_DELEGATE, []string{grantee.String()}, nil, nil, nil); err == nil {
+		t.Error("account address as validator: got no error")
+	}
+}
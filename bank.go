@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"github.com/shopspring/decimal"
-	"log"
 
 	sdkmath "cosmossdk.io/math"
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -17,10 +16,15 @@ import (
 // @param coin the coin denomination to retrieve
 // @return the balance as a string, or an error if the retrieval fails
 func (s *Server) GetBalance(address string, coin string) (string, error) {
+	return s.GetBalanceCtx(context.Background(), address, coin)
+}
+
+// GetBalanceCtx is like GetBalance but honours ctx for cancellation and deadlines.
+func (s *Server) GetBalanceCtx(ctx context.Context, address string, coin string) (string, error) {
 	result := "0"
-	coins, err := s.GetBalanceList(address)
+	coins, err := s.GetBalanceListCtx(ctx, address)
 	if err != nil {
-		log.Printf("error when GetBalanceList by addr: %v, err: %v", address, err.Error())
+		s.log().Error("error when GetBalanceList by addr", "address", address, "err", err)
 		return result, err
 	}
 
@@ -37,25 +41,30 @@ func (s *Server) GetBalance(address string, coin string) (string, error) {
 // @param address the address to query the balances for
 // @return a list of coins representing the balances, or an error if the retrieval fails
 func (s *Server) GetBalanceList(address string) (sdk.Coins, error) {
+	return s.GetBalanceListCtx(context.Background(), address)
+}
+
+// GetBalanceListCtx is like GetBalanceList but honours ctx for cancellation and deadlines.
+func (s *Server) GetBalanceListCtx(ctx context.Context, address string) (sdk.Coins, error) {
 	result := sdk.Coins{}
-	if err := s.KeepGrpcConn(); err != nil {
-		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+	if err := s.KeepGrpcConnCtx(ctx); err != nil {
+		s.log().Error("error when keep grpc conn", "endpoint", s.endPoint, "err", err)
 		return result, err
 	}
 
-	client := banktypes.NewQueryClient(s.Conn)
+	client := banktypes.NewQueryClient(s.conn())
 
 	targetAddr, err := ConvertToCysicAddress(address)
 	if err != nil {
-		log.Printf("error when convert addr: %v to cosmosAddr, err: %v", address, err.Error())
+		s.log().Error("error when convert addr to cosmosAddr", "address", address, "err", err)
 		return result, err
 	}
 	req := &banktypes.QueryAllBalancesRequest{Address: targetAddr}
 
-	resp, err := client.AllBalances(context.Background(), req)
+	resp, err := client.AllBalances(ctx, req)
 	if err != nil {
-		log.Printf("could not query balances: %v", err)
-		return result, err
+		s.log().Error("could not query balances", "err", err)
+		return result, wrapCtxErr(ctx, err)
 	}
 
 	return resp.Balances, nil
@@ -63,48 +72,73 @@ func (s *Server) GetBalanceList(address string) (sdk.Coins, error) {
 
 // Send facilitates the sending of coins from one address to another.
 //
-// @param signer the Signer instance used to sign the transaction
+// @param signer the TxSigner used to sign the transaction
 // @param toAddrStr the address to send coins to
 // @param coin the coin denomination to send
 // @param amount the amount of coins to send
 // @return the transaction hash as a string, or an error if the send operation fails
-func (s *Server) Send(signer Signer, toAddrStr string, coin string, amount sdkmath.Int) (string, error) {
-	targetAddr, err := ConvertToCysicAddress(toAddrStr)
+func (s *Server) Send(signer TxSigner, toAddrStr string, coin string, amount sdkmath.Int) (string, error) {
+	return s.SendCtx(context.Background(), signer, toAddrStr, coin, amount)
+}
+
+// SendCtx is like Send but honours ctx for cancellation and deadlines.
+func (s *Server) SendCtx(ctx context.Context, signer TxSigner, toAddrStr string, coin string, amount sdkmath.Int) (string, error) {
+	sendMsg, err := NewSendMsg(signer.Address().String(), toAddrStr, coin, amount)
 	if err != nil {
-		log.Printf("error when convert addr: %v to cosmosAddr, err: %v", toAddrStr, err.Error())
 		return "", err
 	}
 
-	toAddr, err := sdk.AccAddressFromBech32(targetAddr)
+	return s.buildAndBroadcastCosmosTx(ctx, signer, []sdk.Msg{sendMsg})
+}
+
+// NewSendMsg builds the message Send broadcasts, e.g. to be executed through Exec.
+//
+// @param fromAddrStr the address to send coins from
+// @param toAddrStr the address to send coins to
+// @param coin the coin denomination to send
+// @param amount the amount of coins to send
+// @return the message, or an error if an address is invalid
+func NewSendMsg(fromAddrStr string, toAddrStr string, coin string, amount sdkmath.Int) (sdk.Msg, error) {
+	fromAddr, err := toAccAddress(fromAddrStr)
 	if err != nil {
-		log.Printf("error when convert toAddr: %v, afterConvertAddr: %v, err: %v\n", toAddrStr, targetAddr, err.Error())
-		return "", err
+		logger().Error("error when convert fromAddr", "from", fromAddrStr, "err", err)
+		return nil, err
+	}
+
+	toAddr, err := toAccAddress(toAddrStr)
+	if err != nil {
+		logger().Error("error when convert toAddr", "to", toAddrStr, "err", err)
+		return nil, err
 	}
 
-	sendMsg := banktypes.NewMsgSend(signer.CosmosAddr, toAddr, sdk.Coins{sdk.NewCoin(coin, amount)})
-	return s.buildAndBroadcastCosmosTx(signer, []sdk.Msg{sendMsg})
+	return banktypes.NewMsgSend(fromAddr, toAddr, sdk.Coins{sdk.NewCoin(coin, amount)}), nil
 }
 
 // MultiSend facilitates the sending of coins to multiple addresses in a single transaction.
 //
-// @param signer the Signer instance used to sign the transaction
+// @param signer the TxSigner used to sign the transaction
 // @param toAddrList the list of addresses to send coins to
 // @param coin the coin denomination to send
 // @param amount the amount of coins to send to each address
 // @return the transaction hash as a string, or an error if the send operation fails
-func (s *Server) MultiSend(signer Signer, toAddrList []string, coin string, amount sdkmath.Int) (string, error) {
+func (s *Server) MultiSend(signer TxSigner, toAddrList []string, coin string, amount sdkmath.Int) (string, error) {
+	return s.MultiSendCtx(context.Background(), signer, toAddrList, coin, amount)
+}
+
+// MultiSendCtx is like MultiSend but honours ctx for cancellation and deadlines.
+func (s *Server) MultiSendCtx(ctx context.Context, signer TxSigner, toAddrList []string, coin string, amount sdkmath.Int) (string, error) {
 	coins := sdk.NewCoins(sdk.NewCoin(coin, amount.Mul(sdkmath.NewInt(int64(len(toAddrList))))))
-	in := []banktypes.Input{banktypes.NewInput(signer.CosmosAddr, coins)}
+	in := []banktypes.Input{banktypes.NewInput(signer.Address(), coins)}
 	var out []banktypes.Output
 	for _, toAddr := range toAddrList {
 		toAddrCosmos, err := ConvertToCysicAddress(toAddr)
 		if err != nil {
-			log.Printf("error when convert to addr: %v, err: %v", toAddr, err.Error())
+			s.log().Error("error when convert to addr", "to", toAddr, "err", err)
 			return "", err
 		}
 		to, err := sdk.AccAddressFromBech32(toAddrCosmos)
 		if err != nil {
-			log.Printf("error when conert addr to accAddr, addr: %v, err: %v", toAddrCosmos, err.Error())
+			s.log().Error("error when convert addr to accAddr", "address", toAddrCosmos, "err", err)
 			return "", err
 		}
 
@@ -113,17 +147,22 @@ func (s *Server) MultiSend(signer Signer, toAddrList []string, coin string, amou
 	}
 
 	msg := banktypes.NewMsgMultiSend(in, out)
-	return s.buildAndBroadcastCosmosTx(signer, []sdk.Msg{msg})
+	return s.buildAndBroadcastCosmosTx(ctx, signer, []sdk.Msg{msg})
 }
 
 // MultiSendWithDiffAmount facilitates the sending of different amounts of coins to multiple addresses in a single transaction.
 //
-// @param signer the Signer instance used to sign the transaction
+// @param signer the TxSigner used to sign the transaction
 // @param toAddrList the list of addresses to send coins to
 // @param coinList the list of coin denominations to send
 // @param amountList the list of amounts to send for each coin denomination
 // @return the transaction hash as a string, or an error if the send operation fails
-func (s *Server) MultiSendWithDiffAmount(signer Signer, toAddrList []string, coinList []string, amountList []sdkmath.Int) (string, error) {
+func (s *Server) MultiSendWithDiffAmount(signer TxSigner, toAddrList []string, coinList []string, amountList []sdkmath.Int) (string, error) {
+	return s.MultiSendWithDiffAmountCtx(context.Background(), signer, toAddrList, coinList, amountList)
+}
+
+// MultiSendWithDiffAmountCtx is like MultiSendWithDiffAmount but honours ctx for cancellation and deadlines.
+func (s *Server) MultiSendWithDiffAmountCtx(ctx context.Context, signer TxSigner, toAddrList []string, coinList []string, amountList []sdkmath.Int) (string, error) {
 	if len(toAddrList) != len(coinList) || len(coinList) != len(amountList) {
 		return "", fmt.Errorf("params length not equal, len(toAddr): %v, len(coinList): %v, len(amountList): %v",
 			len(toAddrList), len(coinList), len(amountList))
@@ -145,17 +184,17 @@ func (s *Server) MultiSendWithDiffAmount(signer Signer, toAddrList []string, coi
 		newCoin := sdk.NewCoin(coin, amount)
 		coins = coins.Add(newCoin)
 	}
-	in := []banktypes.Input{banktypes.NewInput(signer.CosmosAddr, coins)}
+	in := []banktypes.Input{banktypes.NewInput(signer.Address(), coins)}
 	var out []banktypes.Output
 	for i, toAddr := range toAddrList {
 		toAddrCosmos, err := ConvertToCysicAddress(toAddr)
 		if err != nil {
-			log.Printf("error when convert to addr: %v, err: %v", toAddr, err.Error())
+			s.log().Error("error when convert to addr", "to", toAddr, "err", err)
 			return "", err
 		}
 		to, err := sdk.AccAddressFromBech32(toAddrCosmos)
 		if err != nil {
-			log.Printf("error when conert addr to accAddr, addr: %v, err: %v", toAddrCosmos, err.Error())
+			s.log().Error("error when convert addr to accAddr", "address", toAddrCosmos, "err", err)
 			return "", err
 		}
 
@@ -164,5 +203,5 @@ func (s *Server) MultiSendWithDiffAmount(signer Signer, toAddrList []string, coi
 	}
 
 	msg := banktypes.NewMsgMultiSend(in, out)
-	return s.buildAndBroadcastCosmosTx(signer, []sdk.Msg{msg})
+	return s.buildAndBroadcastCosmosTx(ctx, signer, []sdk.Msg{msg})
 }
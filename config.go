@@ -1,6 +1,7 @@
 package gosdk
 
 import (
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	ethaccounts "github.com/ethereum/go-ethereum/accounts"
 )
@@ -36,6 +37,12 @@ var (
 
 	// BIP44HDPath is the default BIP44 HD path used on Ethereum.
 	BIP44HDPath = ethaccounts.DefaultBaseDerivationPath.String()
+
+	// CosmosBip44CoinType is the BIP44 coin type of Cosmos secp256k1 keys.
+	CosmosBip44CoinType uint32 = 118
+
+	// CosmosHDPath is the default BIP44 HD path of Cosmos secp256k1 keys, m/44'/118'/0'/0/0.
+	CosmosHDPath = hd.CreateHDPath(CosmosBip44CoinType, 0, 0).String()
 )
 
 // SetBip44CoinType sets the global coin type to be used in hierarchical deterministic wallets.
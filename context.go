@@ -0,0 +1,39 @@
+package gosdk
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// wrapCtxErr makes cancellation and deadline failures distinguishable with errors.Is.
+//
+// If ctx is done, or the gRPC status reports Canceled/DeadlineExceeded, the returned error
+// wraps context.Canceled or context.DeadlineExceeded together with the original error.
+//
+// @param ctx the context the failing call was made with
+// @param err the error returned by the call
+// @return the wrapped error, or err unchanged if it is unrelated to the context
+func wrapCtxErr(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		if err == ctxErr {
+			return err
+		}
+		return fmt.Errorf("%w: %w", ctxErr, err)
+	}
+
+	switch status.Code(err) {
+	case codes.Canceled:
+		return fmt.Errorf("%w: %w", context.Canceled, err)
+	case codes.DeadlineExceeded:
+		return fmt.Errorf("%w: %w", context.DeadlineExceeded, err)
+	}
+
+	return err
+}
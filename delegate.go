@@ -2,7 +2,6 @@ package gosdk
 
 import (
 	"context"
-	"log"
 
 	delegatetypes "github.com/cysic-tech/gosdk/types/delegate"
 
@@ -10,6 +9,7 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	distributiontypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/ethereum/go-ethereum/common"
 )
 
 // QueryDelegatorDelegations retrieves the delegations of a delegator across all validators.
@@ -17,17 +17,22 @@ import (
 // @param delegatorAddress the address of the delegator
 // @return a map where each key is a validator address and the value is a list of coins representing the delegator's delegations to that validator, or an error if the query fails
 func (s *Server) QueryDelegatorDelegations(delegatorAddress string) (map[string][]sdk.Coin, error) {
+	return s.QueryDelegatorDelegationsCtx(context.Background(), delegatorAddress)
+}
+
+// QueryDelegatorDelegationsCtx is like QueryDelegatorDelegations but honours ctx for cancellation and deadlines.
+func (s *Server) QueryDelegatorDelegationsCtx(ctx context.Context, delegatorAddress string) (map[string][]sdk.Coin, error) {
 	result := make(map[string][]sdk.Coin)
-	if err := s.KeepGrpcConn(); err != nil {
-		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+	if err := s.KeepGrpcConnCtx(ctx); err != nil {
+		s.log().Error("error when keep grpc conn", "endpoint", s.endPoint, "err", err)
 		return result, err
 	}
 
-	client := stakingtypes.NewQueryClient(s.Conn)
+	client := stakingtypes.NewQueryClient(s.conn())
 
 	targetAddr, err := ConvertToCysicAddress(delegatorAddress)
 	if err != nil {
-		log.Printf("error when convert addr: %v to cosmosAddr, err: %v", delegatorAddress, err.Error())
+		s.log().Error("error when convert addr to cosmosAddr", "delegator", delegatorAddress, "err", err)
 		return result, err
 	}
 
@@ -35,10 +40,10 @@ func (s *Server) QueryDelegatorDelegations(delegatorAddress string) (map[string]
 		DelegatorAddr: targetAddr,
 	}
 
-	resp, err := client.DelegatorDelegations(context.Background(), req)
+	resp, err := client.DelegatorDelegations(ctx, req)
 	if err != nil {
-		log.Printf("could not query delegateReward: %v", err)
-		return result, err
+		s.log().Error("could not query delegateReward", "err", err)
+		return result, wrapCtxErr(ctx, err)
 	}
 
 	resultMap := make(map[string]map[string]sdk.Coin)
@@ -82,17 +87,22 @@ func (s *Server) QueryDelegatorDelegations(delegatorAddress string) (map[string]
 // @param delegatorAddress the address of the delegator
 // @return a map where each key is a validator address and the value is a list of coins representing the total rewards earned by the delegator from that validator, or an error if the query fails
 func (s *Server) QueryDelegateReward(delegatorAddress string) (map[string][]sdk.Coin, error) {
+	return s.QueryDelegateRewardCtx(context.Background(), delegatorAddress)
+}
+
+// QueryDelegateRewardCtx is like QueryDelegateReward but honours ctx for cancellation and deadlines.
+func (s *Server) QueryDelegateRewardCtx(ctx context.Context, delegatorAddress string) (map[string][]sdk.Coin, error) {
 	result := make(map[string][]sdk.Coin)
-	if err := s.KeepGrpcConn(); err != nil {
-		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+	if err := s.KeepGrpcConnCtx(ctx); err != nil {
+		s.log().Error("error when keep grpc conn", "endpoint", s.endPoint, "err", err)
 		return result, err
 	}
 
-	client := distributiontypes.NewQueryClient(s.Conn)
+	client := distributiontypes.NewQueryClient(s.conn())
 
 	targetAddr, err := ConvertToCysicAddress(delegatorAddress)
 	if err != nil {
-		log.Printf("error when convert addr: %v to cosmosAddr, err: %v", delegatorAddress, err.Error())
+		s.log().Error("error when convert addr to cosmosAddr", "delegator", delegatorAddress, "err", err)
 		return result, err
 	}
 
@@ -100,10 +110,10 @@ func (s *Server) QueryDelegateReward(delegatorAddress string) (map[string][]sdk.
 		DelegatorAddress: targetAddr,
 	}
 
-	resp, err := client.DelegationTotalRewards(context.Background(), req)
+	resp, err := client.DelegationTotalRewards(ctx, req)
 	if err != nil {
-		log.Printf("could not query delegateReward: %v", err)
-		return result, err
+		s.log().Error("could not query delegateReward", "err", err)
+		return result, wrapCtxErr(ctx, err)
 	}
 
 	resultMap := make(map[string]map[string]sdk.Coin)
@@ -145,46 +155,73 @@ func (s *Server) QueryDelegateReward(delegatorAddress string) (map[string][]sdk.
 
 // WithdrawDelegatorReward withdraws rewards for a delegator.
 //
-// @param signer the Signer instance used to sign the transaction
+// @param signer the TxSigner used to sign the transaction
 // @param validatorAddress the address of the validator
 // @return the transaction hash as a string, or an error if the withdrawal fails
-func (s *Server) WithdrawDelegatorReward(signer Signer, validatorAddress string) (string, error) {
-	delegatorAddr := signer.CosmosAddr.String()
+func (s *Server) WithdrawDelegatorReward(signer TxSigner, validatorAddress string) (string, error) {
+	return s.WithdrawDelegatorRewardCtx(context.Background(), signer, validatorAddress)
+}
 
-	msg := &distributiontypes.MsgWithdrawDelegatorReward{
-		DelegatorAddress: delegatorAddr,
-		ValidatorAddress: validatorAddress,
+// WithdrawDelegatorRewardCtx is like WithdrawDelegatorReward but honours ctx for cancellation and deadlines.
+func (s *Server) WithdrawDelegatorRewardCtx(ctx context.Context, signer TxSigner, validatorAddress string) (string, error) {
+	msg, err := NewWithdrawDelegatorRewardMsg(signer.Address().String(), validatorAddress)
+	if err != nil {
+		return "", err
 	}
 
-	if err := s.KeepGrpcConn(); err != nil {
-		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+	if err := s.KeepGrpcConnCtx(ctx); err != nil {
+		s.log().Error("error when keep grpc conn", "endpoint", s.endPoint, "err", err)
 		return "", err
 	}
 
+	return s.buildAndBroadcastCosmosTx(ctx, signer, []sdk.Msg{msg})
+}
+
+// NewWithdrawDelegatorRewardMsg builds the message WithdrawDelegatorReward broadcasts, e.g. to be executed through Exec.
+//
+// @param delegatorAddress the address of the delegator
+// @param validatorAddress the address of the validator
+// @return the message, or an error if it is invalid
+func NewWithdrawDelegatorRewardMsg(delegatorAddress string, validatorAddress string) (sdk.Msg, error) {
+	delegatorAddr, err := ConvertToCysicAddress(delegatorAddress)
+	if err != nil {
+		logger().Error("error when convert addr to cosmosAddr", "delegator", delegatorAddress, "err", err)
+		return nil, err
+	}
+
+	msg := &distributiontypes.MsgWithdrawDelegatorReward{
+		DelegatorAddress: delegatorAddr,
+		ValidatorAddress: validatorAddress,
+	}
 	if err := msg.ValidateBasic(); err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return s.buildAndBroadcastCosmosTx(signer, []sdk.Msg{msg})
+	return msg, nil
 }
 
 // DelegateVeToken delegates veTokens to a validator.
 //
-// @param signer the Signer instance used to sign the transaction
+// @param signer the TxSigner used to sign the transaction
 // @param validatorAddress the address of the validator
 // @param coin the token to delegate
 // @param amount the amount to delegate
 // @return the transaction hash as a string, or an error if the delegation fails
-func (s *Server) DelegateVeToken(signer Signer, validatorAddress string, coin string, amount math.Int) (string, error) {
+func (s *Server) DelegateVeToken(signer TxSigner, validatorAddress string, coin string, amount math.Int) (string, error) {
+	return s.DelegateVeTokenCtx(context.Background(), signer, validatorAddress, coin, amount)
+}
+
+// DelegateVeTokenCtx is like DelegateVeToken but honours ctx for cancellation and deadlines.
+func (s *Server) DelegateVeTokenCtx(ctx context.Context, signer TxSigner, validatorAddress string, coin string, amount math.Int) (string, error) {
 	msg := &delegatetypes.MsgDelegate{
-		Worker:    signer.EthAddr.String(),
+		Worker:    common.BytesToAddress(signer.Address()).String(),
 		Validator: validatorAddress,
 		Token:     coin,
 		Amount:    amount.String(),
 	}
 
-	if err := s.KeepGrpcConn(); err != nil {
-		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+	if err := s.KeepGrpcConnCtx(ctx); err != nil {
+		s.log().Error("error when keep grpc conn", "endpoint", s.endPoint, "err", err)
 		return "", err
 	}
 
@@ -192,61 +229,111 @@ func (s *Server) DelegateVeToken(signer Signer, validatorAddress string, coin st
 		return "", err
 	}
 
-	return s.buildAndBroadcastCosmosTx(signer, []sdk.Msg{msg})
+	return s.buildAndBroadcastCosmosTx(ctx, signer, []sdk.Msg{msg})
 }
 
 // DelegateCGT delegates CGT tokens to a validator.
 //
-// @param signer the Signer instance used to sign the transaction
+// @param signer the TxSigner used to sign the transaction
 // @param validatorAddress the address of the validator
 // @param amount the amount to delegate
 // @return the transaction hash as a string, or an error if the delegation fails
-func (s *Server) DelegateCGT(signer Signer, validatorAddress string, amount math.Int) (string, error) {
+func (s *Server) DelegateCGT(signer TxSigner, validatorAddress string, amount math.Int) (string, error) {
+	return s.DelegateCGTCtx(context.Background(), signer, validatorAddress, amount)
+}
+
+// DelegateCGTCtx is like DelegateCGT but honours ctx for cancellation and deadlines.
+func (s *Server) DelegateCGTCtx(ctx context.Context, signer TxSigner, validatorAddress string, amount math.Int) (string, error) {
+	msg, err := NewDelegateCGTMsg(signer.Address().String(), validatorAddress, amount)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.KeepGrpcConnCtx(ctx); err != nil {
+		s.log().Error("error when keep grpc conn", "endpoint", s.endPoint, "err", err)
+		return "", err
+	}
+
+	return s.buildAndBroadcastCosmosTx(ctx, signer, []sdk.Msg{msg})
+}
+
+// NewDelegateCGTMsg builds the message DelegateCGT broadcasts, e.g. to be executed through Exec.
+//
+// @param delegatorAddress the address of the delegator
+// @param validatorAddress the address of the validator
+// @param amount the amount to delegate
+// @return the message, or an error if it is invalid
+func NewDelegateCGTMsg(delegatorAddress string, validatorAddress string, amount math.Int) (sdk.Msg, error) {
+	delegatorAddr, err := ConvertToCysicAddress(delegatorAddress)
+	if err != nil {
+		logger().Error("error when convert addr to cosmosAddr", "delegator", delegatorAddress, "err", err)
+		return nil, err
+	}
+
 	msg := &stakingtypes.MsgDelegate{
-		DelegatorAddress: signer.CosmosAddr.String(),
+		DelegatorAddress: delegatorAddr,
 		ValidatorAddress: validatorAddress,
 		Amount: sdk.Coin{
 			Denom:  CGTToken,
 			Amount: amount,
 		},
 	}
+	if err := msg.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
 
-	if err := s.KeepGrpcConn(); err != nil {
-		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+// UnDelegateCGT undelegates CGT tokens from a validator.
+//
+// @param signer the TxSigner used to sign the transaction
+// @param validatorAddress the address of the validator
+// @param amount the amount to undelegate
+// @return the transaction hash as a string, or an error if the undelegation fails
+func (s *Server) UnDelegateCGT(signer TxSigner, validatorAddress string, amount math.Int) (string, error) {
+	return s.UnDelegateCGTCtx(context.Background(), signer, validatorAddress, amount)
+}
+
+// UnDelegateCGTCtx is like UnDelegateCGT but honours ctx for cancellation and deadlines.
+func (s *Server) UnDelegateCGTCtx(ctx context.Context, signer TxSigner, validatorAddress string, amount math.Int) (string, error) {
+	msg, err := NewUnDelegateCGTMsg(signer.Address().String(), validatorAddress, amount)
+	if err != nil {
 		return "", err
 	}
 
-	if err := msg.ValidateBasic(); err != nil {
+	if err := s.KeepGrpcConnCtx(ctx); err != nil {
+		s.log().Error("error when keep grpc conn", "endpoint", s.endPoint, "err", err)
 		return "", err
 	}
 
-	return s.buildAndBroadcastCosmosTx(signer, []sdk.Msg{msg})
+	return s.buildAndBroadcastCosmosTx(ctx, signer, []sdk.Msg{msg})
 }
 
-// UnDelegateCGT undelegates CGT tokens from a validator.
+// NewUnDelegateCGTMsg builds the message UnDelegateCGT broadcasts, e.g. to be executed through Exec.
 //
-// @param signer the Signer instance used to sign the transaction
+// @param delegatorAddress the address of the delegator
 // @param validatorAddress the address of the validator
 // @param amount the amount to undelegate
-// @return the transaction hash as a string, or an error if the undelegation fails
-func (s *Server) UnDelegateCGT(signer Signer, validatorAddress string, amount math.Int) (string, error) {
+// @return the message, or an error if it is invalid
+func NewUnDelegateCGTMsg(delegatorAddress string, validatorAddress string, amount math.Int) (sdk.Msg, error) {
+	delegatorAddr, err := ConvertToCysicAddress(delegatorAddress)
+	if err != nil {
+		logger().Error("error when convert addr to cosmosAddr", "delegator", delegatorAddress, "err", err)
+		return nil, err
+	}
+
 	msg := &stakingtypes.MsgUndelegate{
-		DelegatorAddress: signer.CosmosAddr.String(),
+		DelegatorAddress: delegatorAddr,
 		ValidatorAddress: validatorAddress,
 		Amount: sdk.Coin{
 			Denom:  CGTToken,
 			Amount: amount,
 		},
 	}
-
-	if err := s.KeepGrpcConn(); err != nil {
-		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
-		return "", err
-	}
-
 	if err := msg.ValidateBasic(); err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return s.buildAndBroadcastCosmosTx(signer, []sdk.Msg{msg})
+	return msg, nil
 }
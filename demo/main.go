@@ -1,36 +1,31 @@
 package main
 
 import (
-	"context"
 	"fmt"
 	"log"
-	"strings"
-	"time"
+	"log/slog"
 
 	cysicSDK "github.com/cysic-tech/gosdk"
 	govTokenTypes "github.com/cysic-tech/gosdk/types/govtoken"
 
 	sdkmath "cosmossdk.io/math"
 	"github.com/cosmos/cosmos-sdk/crypto/hd"
-	txTypes "github.com/cosmos/cosmos-sdk/types/tx"
 	"github.com/shopspring/decimal"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 var (
 	defaultServer *cysicSDK.Server
 	signer        *cysicSDK.Signer
-
-	defaultEndpoint = ""
 )
 
 func init() {
+	// the SDK logs nothing unless given a logger
+	cysicSDK.SetDefaultLogger(slog.Default())
+
 	chainEndpoint := ""
 	chainID := "cysicmint_9001-1" // testnet
 	gasCoin := "CYS"
 	gasPrice := int64(10)
-	defaultEndpoint = chainEndpoint
 
 	mnemonic := ""
 	coinType := uint32(1)
@@ -133,7 +128,6 @@ func delegateCGT(signer cysicSDK.Signer, validatorAddr string, amount sdkmath.In
 
 	fmt.Printf("delegateCGT finish, txHash: %v\n", txHash)
 	waitTxFinish(txHash)
-	getTx(txHash)
 
 	fmt.Print("after delegate CGT: ")
 	printBalance(signer.EthAddr.String())
@@ -153,7 +147,6 @@ func delegateVeToken(signer cysicSDK.Signer, validatorAddr string, coin string,
 
 	fmt.Printf("delegateVeToken finish, txHash: %v\n", txHash)
 	waitTxFinish(txHash)
-	getTx(txHash)
 
 	fmt.Print("after delegate : ", coin)
 	printBalance(signer.EthAddr.String())
@@ -173,7 +166,6 @@ func undelegateCGT(signer cysicSDK.Signer, validatorAddr string, amount sdkmath.
 	}
 	fmt.Printf("undelegateCGT finish, txHash: %v\n", txHash)
 	waitTxFinish(txHash)
-	getTx(txHash)
 
 	fmt.Print("after undelegate to CGT: ")
 	getDelegatorDelegation(signer.EthAddr.String())
@@ -201,7 +193,6 @@ func claimReward(signer cysicSDK.Signer, validatorAddr string) {
 		return
 	}
 	waitTxFinish(txHash)
-	getTx(txHash)
 
 	fmt.Print("after claimReward: ")
 	printBalance(signer.EthAddr.String())
@@ -244,60 +235,19 @@ func convertToCYS(signer cysicSDK.Signer, amount sdkmath.Int) {
 }
 
 func waitTxFinish(txHash string) {
-	conn, err := grpc.Dial(defaultEndpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	result, err := defaultServer.AwaitTx(txHash)
 	if err != nil {
-		log.Printf("error when new grpc client: %s", err.Error())
+		fmt.Println(fmt.Sprintf("error when wait tx: %v, err: %v", txHash, err.Error()))
 		return
 	}
 
-	txClient := txTypes.NewServiceClient(conn)
-	defer conn.Close()
-
-	for i := 0; i < 15; i++ {
-		_, err = txClient.GetTx(context.Background(), &txTypes.GetTxRequest{Hash: txHash})
-		if err != nil {
-			if strings.Index(err.Error(), "tx not found") >= 0 {
-				log.Printf("start wait tx: %v packed", txHash)
-				<-time.NewTimer(time.Second).C
-				continue
-			}
-
-			log.Printf("error when get tx: %v, err: %v", txHash, err.Error())
-			return
-		}
-	}
-}
-
-func getTx(txHash string) {
-	conn, err := grpc.Dial(defaultServer.EndPoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		fmt.Println(fmt.Sprintf("error when new grpc client: %s\n", err.Error()))
-		return
-	}
-	defer conn.Close()
-	txClient := txTypes.NewServiceClient(conn)
-
-	txResp, err := txClient.GetTx(context.Background(), &txTypes.GetTxRequest{Hash: txHash})
-	if err != nil {
-		fmt.Println(fmt.Sprintf("error when get tx: %v, err: %v", txHash, err.Error()))
-		return
-	}
-
-	fmt.Println("tx: ", txResp.TxResponse.TxHash, " in", txResp.TxResponse.Height,
-		", fee payer:", txResp.Tx.FeePayer().String(), ", seq:", txResp.Tx.AuthInfo.SignerInfos[0].Sequence, ", is:", txResp.Tx.Body.Messages[0].TypeUrl)
-
-	// not packed
-	if txResp.TxResponse.Height == 0 {
-		fmt.Println(txHash, " not packed")
-		return
-	}
+	fmt.Println("tx: ", result.TxHash, " in", result.Height, ", gas used:", result.GasUsed, "/", result.GasWanted)
 
 	// tx failed
-	if txResp.TxResponse.Code != 0 {
-		fmt.Println(txHash, " failed, ", txResp.TxResponse.RawLog)
+	if !result.Succeeded() {
+		fmt.Println(txHash, " failed, ", result.RawLog)
 		return
 	}
 
-	fmt.Println(txHash, "logs ", txResp.TxResponse.RawLog)
-	return
+	fmt.Println(txHash, "logs ", result.RawLog)
 }
@@ -0,0 +1,194 @@
+package gosdk
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cysic-tech/gosdk/crypto/ethsecp256k1"
+	"github.com/cysic-tech/gosdk/ethereum/eip712"
+	cysicTypes "github.com/cysic-tech/gosdk/types/cysic"
+
+	sdkClient "github.com/cosmos/cosmos-sdk/client"
+	codecTypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	"github.com/cosmos/cosmos-sdk/x/auth/migrations/legacytx"
+	authSigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	authTx "github.com/cosmos/cosmos-sdk/x/auth/tx"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// TxSignMode selects what a transaction's signature is computed over.
+type TxSignMode int
+
+const (
+	// SignModeDefault uses the sign mode of the Server, SignModeDirect unless WithSignMode is given.
+	SignModeDefault TxSignMode = iota
+	// SignModeDirect signs the protobuf SignDoc.
+	SignModeDirect
+	// SignModeEIP712 signs the EIP-712 typed data of the amino JSON SignDoc, as MetaMask does,
+	// and carries the signature in an ExtensionOptionsWeb3Tx. All messages must be of one
+	// type, support amino JSON and have the signer as their first signer.
+	SignModeEIP712
+)
+
+// String returns the name of the mode.
+func (m TxSignMode) String() string {
+	switch m {
+	case SignModeDefault:
+		return "default"
+	case SignModeDirect:
+		return "direct"
+	case SignModeEIP712:
+		return "eip712"
+	}
+
+	return fmt.Sprintf("TxSignMode(%d)", int(m))
+}
+
+// parseTxSignMode parses the name of a sign mode as returned by String.
+func parseTxSignMode(name string) (TxSignMode, error) {
+	for _, mode := range []TxSignMode{SignModeDefault, SignModeDirect, SignModeEIP712} {
+		if mode.String() == name {
+			return mode, nil
+		}
+	}
+
+	return SignModeDefault, fmt.Errorf("unknown sign mode %v", name)
+}
+
+// protoSignMode returns the sign mode recorded in the signer info.
+func (m TxSignMode) protoSignMode() signing.SignMode {
+	if m == SignModeEIP712 {
+		return signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON
+	}
+
+	return signMode
+}
+
+// WithTxSignMode sets the sign mode of one transaction.
+//
+// @param mode the sign mode
+func WithTxSignMode(mode TxSignMode) TxOption {
+	return func(o *txOptions) {
+		o.signMode = mode
+	}
+}
+
+// validateEIP712Msgs checks that msgList can be signed over EIP-712 typed data by signer.
+//
+// @param signer the TxSigner that signs the transaction
+// @param msgList the messages of the transaction
+// @return an error if the messages can't be represented as legacy EIP-712 typed data
+func validateEIP712Msgs(signer TxSigner, msgList []sdk.Msg) error {
+	if len(msgList) == 0 {
+		return fmt.Errorf("msg list is empty")
+	}
+
+	msgType := sdk.MsgTypeURL(msgList[0])
+	for _, msg := range msgList {
+		if _, ok := msg.(legacytx.LegacyMsg); !ok {
+			return fmt.Errorf("msg %v does not support EIP-712 signing", sdk.MsgTypeURL(msg))
+		}
+		if sdk.MsgTypeURL(msg) != msgType {
+			return fmt.Errorf("EIP-712 transactions can't mix msg types %v and %v", msgType, sdk.MsgTypeURL(msg))
+		}
+	}
+	if signers := msgList[0].GetSigners(); len(signers) == 0 || !signers[0].Equals(signer.Address()) {
+		return fmt.Errorf("EIP-712 transactions must be signed by the first signer of the first msg")
+	}
+
+	return nil
+}
+
+// setWeb3Extension attaches the ExtensionOptionsWeb3Tx carrying sig to the transaction.
+//
+// @param txBuilder the transaction
+// @param chainID the Cosmos chain ID, whose EIP-155 part becomes the typed data chain ID
+// @param feePayer the account paying the fee, which signed the typed data
+// @param sig the EIP-712 signature, empty before signing
+// @return an error if the chain ID has no EIP-155 part
+func setWeb3Extension(txBuilder sdkClient.TxBuilder, chainID string, feePayer sdk.AccAddress, sig []byte) error {
+	ethChainID, err := cysicTypes.ParseChainID(chainID)
+	if err != nil {
+		return err
+	}
+
+	option, err := codecTypes.NewAnyWithValue(&cysicTypes.ExtensionOptionsWeb3Tx{
+		TypedDataChainID: ethChainID.Uint64(),
+		FeePayer:         feePayer.String(),
+		FeePayerSig:      sig,
+	})
+	if err != nil {
+		return err
+	}
+
+	extBuilder, ok := txBuilder.(authTx.ExtensionOptionsTxBuilder)
+	if !ok {
+		return fmt.Errorf("tx builder does not support extension options")
+	}
+	extBuilder.SetExtensionOptions(option)
+	return nil
+}
+
+// eip712SignHash returns the hash a signer signs in SignModeEIP712: the EIP-712 hash of the
+// typed data wrapping its amino JSON SignDoc and naming the fee payer, the digest MetaMask signs.
+//
+// @param txBuilder the transaction
+// @param signerData the chain ID, account number and sequence of the signer
+// @return the 32 byte hash, or an error if the transaction has no EIP-712 representation
+func eip712SignHash(txBuilder sdkClient.TxBuilder, signerData authSigning.SignerData) ([]byte, error) {
+	tx := txBuilder.GetTx()
+	msgs := tx.GetMsgs()
+	if len(msgs) == 0 {
+		return nil, fmt.Errorf("msg list is empty")
+	}
+
+	ethChainID, err := cysicTypes.ParseChainID(signerData.ChainID)
+	if err != nil {
+		return nil, err
+	}
+
+	signDoc := legacytx.StdSignBytes(
+		signerData.ChainID, signerData.AccountNumber, signerData.Sequence, tx.GetTimeoutHeight(),
+		legacytx.StdFee{Amount: tx.GetFee(), Gas: tx.GetGas()}, msgs, tx.GetMemo(), nil,
+	)
+	typedData, err := eip712.LegacyWrapTxToTypedData(
+		interfaceRegistry, ethChainID.Uint64(), msgs[0], signDoc,
+		&eip712.FeeDelegationOptions{FeePayer: tx.FeePayer()},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, fmt.Errorf("could not hash EIP-712 typed data: %w", err)
+	}
+
+	return hash, nil
+}
+
+// signEIP712Hash signs an EIP-712 hash with an Ethereum key, with the recovery ID offset by
+// 27 as Web3 wallets return it.
+//
+// @param ctx the context controlling cancellation of remote signers
+// @param signer the signer
+// @param hash the EIP-712 hash
+// @return the 65 byte signature, or ErrNotEthKey if the signer has no Ethereum key
+func signEIP712Hash(ctx context.Context, signer TxSigner, hash []byte) ([]byte, error) {
+	if _, ok := signer.PubKey().(*ethsecp256k1.PubKey); !ok {
+		return nil, ErrNotEthKey
+	}
+
+	sig, err := signer.SignBytes(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if len(sig) == crypto.SignatureLength && sig[crypto.RecoveryIDOffset] < 27 {
+		sig[crypto.RecoveryIDOffset] += 27
+	}
+
+	return sig, nil
+}
@@ -0,0 +1,144 @@
+package gosdk
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/cysic-tech/gosdk/crypto/ethsecp256k1"
+	cysicTypes "github.com/cysic-tech/gosdk/types/cysic"
+
+	sdkmath "cosmossdk.io/math"
+	codecTypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	"github.com/cosmos/cosmos-sdk/x/auth/migrations/legacytx"
+	authSigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// web3Extension returns the ExtensionOptionsWeb3Tx of an encoded transaction.
+func web3Extension(t *testing.T, txBytes []byte) (authSigning.Tx, *cysicTypes.ExtensionOptionsWeb3Tx) {
+	t.Helper()
+
+	decoded, err := txConfig.TxDecoder()(txBytes)
+	if err != nil {
+		t.Fatalf("decode tx: %v", err)
+	}
+	tx, ok := decoded.(interface {
+		authSigning.Tx
+		GetExtensionOptions() []*codecTypes.Any
+	})
+	if !ok {
+		t.Fatalf("tx %T has no extension options", decoded)
+	}
+	options := tx.GetExtensionOptions()
+	if len(options) != 1 || options[0].TypeUrl != "/cysicmint.types.v1.ExtensionOptionsWeb3Tx" {
+		t.Fatalf("extension options = %v, want one ExtensionOptionsWeb3Tx", options)
+	}
+
+	ext := &cysicTypes.ExtensionOptionsWeb3Tx{}
+	if err := ext.Unmarshal(options[0].Value); err != nil {
+		t.Fatalf("decode web3 extension: %v", err)
+	}
+
+	return tx, ext
+}
+
+func TestSignEIP712(t *testing.T) {
+	const chainID = "cysicmint_4242-2"
+	node := newMockChain().serve(t)
+	s, err := NewServer(node.addr, chainID)
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+
+	signer := newTestSigner(t)
+	msg, err := NewSendMsg(signer.Address().String(), newTestSigner(t).Address().String(), CYSToken, sdkmath.NewInt(1))
+	if err != nil {
+		t.Fatalf("new send msg: %v", err)
+	}
+	const accNumber, sequence = 12, 3
+	opts := s.newTxOptions([]TxOption{WithTxSignMode(SignModeEIP712)})
+	txBytes, err := s.signTx(context.Background(), signer, accNumber, sequence, []sdk.Msg{msg}, 200_000, FeeQuote{GasPrice: sdkmath.NewInt(1)}, opts)
+	if err != nil {
+		t.Fatalf("sign tx: %v", err)
+	}
+
+	tx, ext := web3Extension(t, txBytes)
+	if ext.TypedDataChainID != 4242 {
+		t.Errorf("typed data chain ID = %d, want the EIP-155 chain ID 4242", ext.TypedDataChainID)
+	}
+	if ext.FeePayer != signer.Address().String() {
+		t.Errorf("web3 fee payer = %v, want the signer %v", ext.FeePayer, signer.Address())
+	}
+
+	sig := ext.FeePayerSig
+	if len(sig) != crypto.SignatureLength {
+		t.Fatalf("signature has %d bytes, want %d", len(sig), crypto.SignatureLength)
+	}
+	if v := sig[crypto.RecoveryIDOffset]; v != 27 && v != 28 {
+		t.Errorf("V = %d, want 27 or 28 as Web3 wallets return it", v)
+	}
+
+	sigs, err := tx.GetSignaturesV2()
+	if err != nil {
+		t.Fatalf("get signatures: %v", err)
+	}
+	if data, ok := sigs[0].Data.(*signing.SingleSignatureData); !ok || data.SignMode != signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON || len(data.Signature) != 0 {
+		t.Errorf("cosmos signature = %+v, want an empty amino JSON signature", sigs[0].Data)
+	}
+
+	// the signature is over the EIP-712 hash of the amino JSON sign doc
+	txBuilder, err := txConfig.WrapTxBuilder(tx)
+	if err != nil {
+		t.Fatalf("wrap tx: %v", err)
+	}
+	hash, err := eip712SignHash(txBuilder, authSigning.SignerData{ChainID: chainID, AccountNumber: accNumber, Sequence: sequence})
+	if err != nil {
+		t.Fatalf("eip712 hash: %v", err)
+	}
+	recovered, err := crypto.SigToPub(hash, append(bytes.Clone(sig[:crypto.RecoveryIDOffset]), sig[crypto.RecoveryIDOffset]-27))
+	if err != nil {
+		t.Fatalf("recover public key: %v", err)
+	}
+	pubKey := signer.PubKey().(*ethsecp256k1.PubKey)
+	if !bytes.Equal(crypto.CompressPubkey(recovered), pubKey.Key) {
+		t.Errorf("signature recovers another public key")
+	}
+
+	// the chain verifies the EIP-712 form of the amino JSON sign doc
+	signDoc := legacytx.StdSignBytes(chainID, accNumber, sequence, tx.GetTimeoutHeight(),
+		legacytx.StdFee{Amount: tx.GetFee(), Gas: tx.GetGas()}, tx.GetMsgs(), tx.GetMemo(), nil)
+	if !pubKey.VerifySignature(signDoc, sig) {
+		t.Errorf("signature does not verify against the amino JSON sign doc")
+	}
+	other := sdk.Coins{sdk.NewCoin(CYSToken, sdkmath.NewInt(1))}
+	tampered := legacytx.StdSignBytes(chainID, accNumber, sequence, tx.GetTimeoutHeight(),
+		legacytx.StdFee{Amount: other, Gas: tx.GetGas()}, tx.GetMsgs(), tx.GetMemo(), nil)
+	if pubKey.VerifySignature(tampered, sig) {
+		t.Errorf("signature verifies against a sign doc with another fee")
+	}
+}
+
+func TestSetWeb3ExtensionChainID(t *testing.T) {
+	addr := newTestSigner(t).Address()
+	for chainID, want := range map[string]uint64{testChainID: 9001, "cysicmint_4242-2": 4242, "cysic_1-1": 1} {
+		txBuilder := txConfig.NewTxBuilder()
+		if err := setWeb3Extension(txBuilder, chainID, addr, []byte{1}); err != nil {
+			t.Fatalf("set web3 extension for %v: %v", chainID, err)
+		}
+		txBytes, err := txConfig.TxEncoder()(txBuilder.GetTx())
+		if err != nil {
+			t.Fatalf("encode tx: %v", err)
+		}
+		if _, ext := web3Extension(t, txBytes); ext.TypedDataChainID != want {
+			t.Errorf("chain ID %v: typed data chain ID = %d, want %d", chainID, ext.TypedDataChainID, want)
+		}
+	}
+
+	if err := setWeb3Extension(txConfig.NewTxBuilder(), "cysicmint", addr, nil); err == nil {
+		t.Errorf("chain ID without EIP-155 part accepted, want an error")
+	}
+}
@@ -0,0 +1,169 @@
+package gosdk
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	govTokenTypes "github.com/cysic-tech/gosdk/types/govtoken"
+
+	errorsmod "cosmossdk.io/errors"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Errors returned by the SDK, to be matched with errors.Is. A *TxError also matches the error
+// registered on the chain for its codespace and code, e.g. sdkerrors.ErrInsufficientFee or
+// govtoken ErrNonExchangeable.
+var (
+	// ErrTxFailed matches every *TxError: the chain rejected or failed to execute a transaction.
+	ErrTxFailed = errors.New("tx failed")
+	// ErrInsufficientFunds means an account can't pay an amount, in the bank or govtoken module.
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	// ErrSequenceMismatch means a transaction was signed with a wrong account sequence.
+	ErrSequenceMismatch = errors.New("account sequence mismatch")
+	// ErrOutOfGas means a transaction ran out of gas.
+	ErrOutOfGas = errors.New("out of gas")
+	// ErrAccountNotFound means an account does not exist on chain, e.g. it never received funds.
+	ErrAccountNotFound = errors.New("account not found")
+	// ErrTxNotFound means a transaction is not known to the node, e.g. not included yet.
+	ErrTxNotFound = errors.New("tx not found")
+)
+
+// abciSentinels maps errors registered on the chain to the SDK errors they are matched as.
+var abciSentinels = []struct {
+	registered *errorsmod.Error
+	sentinel   error
+}{
+	{sdkerrors.ErrInsufficientFunds, ErrInsufficientFunds},
+	{govTokenTypes.ErrInsufficientFunds, ErrInsufficientFunds},
+	{sdkerrors.ErrWrongSequence, ErrSequenceMismatch},
+	{sdkerrors.ErrOutOfGas, ErrOutOfGas},
+	{sdkerrors.ErrUnknownAddress, ErrAccountNotFound},
+}
+
+// simulateErrors are the registered errors a failed simulation is recognized as, besides
+// those of abciSentinels. Errors sharing a description resolve to the first one listed.
+var simulateErrors = []*errorsmod.Error{
+	sdkerrors.ErrInsufficientFee,
+	sdkerrors.ErrMempoolIsFull,
+	sdkerrors.ErrUnauthorized,
+	sdkerrors.ErrInvalidAddress,
+	sdkerrors.ErrInvalidCoins,
+	sdkerrors.ErrInvalidPubKey,
+	sdkerrors.ErrInvalidRequest,
+	sdkerrors.ErrTxDecode,
+	sdkerrors.ErrTxTimeoutHeight,
+	govTokenTypes.ErrInvalidDenom,
+	govTokenTypes.ErrInvalidOwner,
+	govTokenTypes.ErrInvalidRate,
+	govTokenTypes.ErrNonExchangeable,
+}
+
+var (
+	// simulateGasInfo is the gas report, and anything after it, the node appends to the log of a failed simulation.
+	simulateGasInfo = regexp.MustCompile(`(?s) With gas wanted: .*$`)
+	// errorLocation is the [file:line] where the chain created the error, appended by errorsmod with %v.
+	errorLocation = regexp.MustCompile(` \[[^\]]+:\d+\]$`)
+)
+
+// statusPrefix prefixes the message of a gRPC status the node forwards from its ABCI query.
+const statusPrefix = "rpc error: code = Unknown desc = "
+
+// TxError is returned when the chain rejects a transaction in simulation or CheckTx, or fails
+// to execute it.
+//
+// errors.Is matches it against ErrTxFailed, the SDK error its code maps to (ErrInsufficientFunds,
+// ErrSequenceMismatch, ErrOutOfGas, ErrAccountNotFound) and the chain's registered module error.
+type TxError struct {
+	TxHash    string
+	Codespace string
+	Code      uint32
+	// Log is the raw log of the transaction, describing the failure.
+	Log string
+}
+
+// TxResponseError returns the error of a transaction response, e.g. from BroadcastTx.
+//
+// @param resp the transaction response
+// @return a *TxError if the code is not zero, nil otherwise
+func TxResponseError(resp *sdk.TxResponse) error {
+	if resp == nil || resp.Code == 0 {
+		return nil
+	}
+
+	return &TxError{TxHash: resp.TxHash, Codespace: resp.Codespace, Code: resp.Code, Log: resp.RawLog}
+}
+
+func (e *TxError) Error() string {
+	if e.Log != "" {
+		return e.Log
+	}
+
+	return fmt.Sprintf("tx failed, codespace: %v, code: %v", e.Codespace, e.Code)
+}
+
+// Unwrap returns ErrTxFailed, the registered error of the codespace and code, and the SDK error it maps to.
+func (e *TxError) Unwrap() []error {
+	errs := []error{ErrTxFailed, errorsmod.ABCIError(e.Codespace, e.Code, e.Log)}
+	for _, mapping := range abciSentinels {
+		if e.is(mapping.registered) {
+			errs = append(errs, mapping.sentinel)
+		}
+	}
+
+	return errs
+}
+
+// is reports whether the error has the codespace and code of registered.
+func (e *TxError) is(registered *errorsmod.Error) bool {
+	return e.Codespace == registered.Codespace() && e.Code == registered.ABCICode()
+}
+
+// simulateError converts an error of the Simulate RPC into a *TxError. The node fails a
+// simulation with an Unknown status whose message is the ABCI log followed by the gas used,
+// without codespace and code; they are recovered from the description of the registered
+// error that ends the log, e.g. "account sequence mismatch, expected 5, got 4: incorrect
+// account sequence [cosmos/cosmos-sdk@v0.46.16/x/auth/ante/sigverify.go:264]".
+//
+// @param err the error returned by Simulate
+// @return a *TxError, or err unchanged if it is not a rejection of the transaction
+func simulateError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.Unknown {
+		return err
+	}
+
+	log := st.Message()
+	for strings.HasPrefix(log, statusPrefix) {
+		log = strings.TrimPrefix(log, statusPrefix)
+	}
+	log = simulateGasInfo.ReplaceAllString(log, "")
+	log = errorLocation.ReplaceAllString(strings.TrimSpace(log), "")
+
+	var match *errorsmod.Error
+	for _, registered := range simulateCandidates() {
+		desc := registered.Error()
+		if (log == desc || strings.HasSuffix(log, ": "+desc)) && (match == nil || len(desc) > len(match.Error())) {
+			match = registered
+		}
+	}
+	if match == nil {
+		return err
+	}
+
+	return &TxError{Codespace: match.Codespace(), Code: match.ABCICode(), Log: log}
+}
+
+// simulateCandidates returns the registered errors of abciSentinels followed by simulateErrors.
+func simulateCandidates() []*errorsmod.Error {
+	candidates := make([]*errorsmod.Error, 0, len(abciSentinels)+len(simulateErrors))
+	for _, mapping := range abciSentinels {
+		candidates = append(candidates, mapping.registered)
+	}
+
+	return append(candidates, simulateErrors...)
+}
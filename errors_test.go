@@ -0,0 +1,151 @@
+package gosdk
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	govTokenTypes "github.com/cysic-tech/gosdk/types/govtoken"
+
+	sdkmath "cosmossdk.io/math"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const broadcastMethod = "/cosmos.tx.v1beta1.Service/BroadcastTx"
+
+func TestTxErrorMatchesSentinels(t *testing.T) {
+	for _, stage := range []string{"simulate", "broadcast"} {
+		t.Run(stage, func(t *testing.T) {
+			chain := newMockChain()
+			node := chain.serve(t)
+			opts := []Option{WithRetryPolicy(NoRetry())}
+			if stage == "broadcast" {
+				opts = append(opts, WithFixedGas())
+			}
+			s := newTestServer(t, node, opts...)
+
+			signer := newTestSigner(t)
+			chain.fund(signer.Address(), testCoins(1_000))
+			recipient := newTestSigner(t).Address().String()
+
+			cases := []struct {
+				name     string
+				send     func() error
+				sentinel error
+				code     uint32
+			}{
+				{
+					name: "insufficient funds",
+					send: func() error {
+						_, err := s.Send(signer, recipient, CYSToken, sdkmath.NewInt(2_000))
+						return err
+					},
+					sentinel: ErrInsufficientFunds,
+					code:     sdkerrors.ErrInsufficientFunds.ABCICode(),
+				},
+				{
+					name: "sequence mismatch",
+					send: func() error {
+						_, err := s.Send(signer.WithNonce(5), recipient, CYSToken, sdkmath.NewInt(1))
+						return err
+					},
+					sentinel: ErrSequenceMismatch,
+					code:     sdkerrors.ErrWrongSequence.ABCICode(),
+				},
+				{
+					name: "out of gas",
+					send: func() error {
+						if err := s.SetGasLimit(mockTxGas); err != nil {
+							return err
+						}
+						defer func() { _ = s.SetGasLimit(gasLimit) }()
+
+						_, err := s.Send(signer, recipient, CYSToken, sdkmath.NewInt(1))
+						return err
+					},
+					sentinel: ErrOutOfGas,
+					code:     sdkerrors.ErrOutOfGas.ABCICode(),
+				},
+			}
+			for _, c := range cases {
+				broadcasts := node.callCount(broadcastMethod)
+				err := c.send()
+
+				var txErr *TxError
+				if !errors.As(err, &txErr) {
+					t.Errorf("%v: got %v, want a *TxError", c.name, err)
+					continue
+				}
+				if txErr.Codespace != sdkerrors.RootCodespace || txErr.Code != c.code {
+					t.Errorf("%v: codespace %q code %d, want %q %d", c.name, txErr.Codespace, txErr.Code, sdkerrors.RootCodespace, c.code)
+				}
+				if !errors.Is(err, c.sentinel) || !errors.Is(err, ErrTxFailed) {
+					t.Errorf("%v: %v does not match %v and ErrTxFailed", c.name, err, c.sentinel)
+				}
+
+				broadcast := node.callCount(broadcastMethod) > broadcasts
+				if broadcast != (stage == "broadcast") {
+					t.Errorf("%v: rejected at broadcast = %v, want %v", c.name, broadcast, stage == "broadcast")
+				}
+			}
+		})
+	}
+}
+
+func TestSimulateError(t *testing.T) {
+	location := " [cosmos/cosmos-sdk@v0.46.16/x/auth/ante/sigverify.go:264]"
+	gasInfo := " With gas wanted: '15000000' and gas used: '41234' "
+
+	cases := []struct {
+		name       string
+		err        error
+		registered error
+		log        string
+	}{
+		{
+			name:       "sequence mismatch",
+			err:        status.Error(codes.Unknown, "account sequence mismatch, expected 5, got 4: incorrect account sequence"+location+gasInfo),
+			registered: sdkerrors.ErrWrongSequence,
+			log:        "account sequence mismatch, expected 5, got 4: incorrect account sequence",
+		},
+		{
+			name:       "forwarded from the abci query",
+			err:        status.Error(codes.Unknown, statusPrefix+"10acys is smaller than 20acys: insufficient funds"+location+gasInfo+": unknown request"),
+			registered: sdkerrors.ErrInsufficientFunds,
+			log:        "10acys is smaller than 20acys: insufficient funds",
+		},
+		{
+			name:       "module error",
+			err:        status.Error(codes.Unknown, "failed to execute message; message index: 0: tokens are not exchangeable"+gasInfo),
+			registered: govTokenTypes.ErrNonExchangeable,
+			log:        "failed to execute message; message index: 0: tokens are not exchangeable",
+		},
+	}
+	for _, c := range cases {
+		err := simulateError(c.err)
+
+		var txErr *TxError
+		if !errors.As(err, &txErr) {
+			t.Errorf("%v: got %v, want a *TxError", c.name, err)
+			continue
+		}
+		if !errors.Is(err, c.registered) {
+			t.Errorf("%v: %v does not match %v", c.name, err, c.registered)
+		}
+		if txErr.Log != c.log {
+			t.Errorf("%v: log %q, want %q", c.name, txErr.Log, c.log)
+		}
+	}
+
+	for _, err := range []error{
+		status.Error(codes.Unavailable, "connection refused"),
+		status.Error(codes.Unknown, "unrecognized failure"+gasInfo),
+		fmt.Errorf("not a status"),
+	} {
+		if got := simulateError(err); got != err {
+			t.Errorf("simulateError(%v) = %v, want it unchanged", err, got)
+		}
+	}
+}
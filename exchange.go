@@ -1,8 +1,8 @@
 package gosdk
 
 import (
+	"context"
 	"fmt"
-	"log"
 
 	govTokenTypes "github.com/cysic-tech/gosdk/types/govtoken"
 
@@ -11,12 +11,17 @@ import (
 
 // ExchangeToCGT exchanges tokens to governance tokens.
 //
-// @param signer the Signer instance used to sign the transaction
+// @param signer the TxSigner used to sign the transaction
 // @param exchangeDetail the exchange details
 // @return the transaction hash as a string, or an error if the exchange fails
-func (s *Server) ExchangeToCGT(signer Signer, exchangeDetail *govTokenTypes.MsgExchangeToGovToken) (string, error) {
-	if err := s.KeepGrpcConn(); err != nil {
-		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+func (s *Server) ExchangeToCGT(signer TxSigner, exchangeDetail *govTokenTypes.MsgExchangeToGovToken) (string, error) {
+	return s.ExchangeToCGTCtx(context.Background(), signer, exchangeDetail)
+}
+
+// ExchangeToCGTCtx is like ExchangeToCGT but honours ctx for cancellation and deadlines.
+func (s *Server) ExchangeToCGTCtx(ctx context.Context, signer TxSigner, exchangeDetail *govTokenTypes.MsgExchangeToGovToken) (string, error) {
+	if err := s.KeepGrpcConnCtx(ctx); err != nil {
+		s.log().Error("error when keep grpc conn", "endpoint", s.endPoint, "err", err)
 		return "", err
 	}
 
@@ -28,9 +33,9 @@ func (s *Server) ExchangeToCGT(signer Signer, exchangeDetail *govTokenTypes.MsgE
 		return "", err
 	}
 
-	txHash, err := s.buildAndBroadcastCosmosTx(signer, []sdk.Msg{exchangeDetail})
+	txHash, err := s.buildAndBroadcastCosmosTx(ctx, signer, []sdk.Msg{exchangeDetail})
 	if err != nil {
-		return "", fmt.Errorf("create token failed, err: %v", err)
+		return "", fmt.Errorf("create token failed, err: %w", err)
 	}
 
 	return txHash, nil
@@ -38,12 +43,17 @@ func (s *Server) ExchangeToCGT(signer Signer, exchangeDetail *govTokenTypes.MsgE
 
 // ExchangeToCYS exchanges tokens to platform tokens.
 //
-// @param signer the Signer instance used to sign the transaction
+// @param signer the TxSigner used to sign the transaction
 // @param exchangeDetail the exchange details
 // @return the transaction hash as a string, or an error if the exchange fails
-func (s *Server) ExchangeToCYS(signer Signer, exchangeDetail *govTokenTypes.MsgExchangeToPlatformToken) (string, error) {
-	if err := s.KeepGrpcConn(); err != nil {
-		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+func (s *Server) ExchangeToCYS(signer TxSigner, exchangeDetail *govTokenTypes.MsgExchangeToPlatformToken) (string, error) {
+	return s.ExchangeToCYSCtx(context.Background(), signer, exchangeDetail)
+}
+
+// ExchangeToCYSCtx is like ExchangeToCYS but honours ctx for cancellation and deadlines.
+func (s *Server) ExchangeToCYSCtx(ctx context.Context, signer TxSigner, exchangeDetail *govTokenTypes.MsgExchangeToPlatformToken) (string, error) {
+	if err := s.KeepGrpcConnCtx(ctx); err != nil {
+		s.log().Error("error when keep grpc conn", "endpoint", s.endPoint, "err", err)
 		return "", err
 	}
 
@@ -55,9 +65,9 @@ func (s *Server) ExchangeToCYS(signer Signer, exchangeDetail *govTokenTypes.MsgE
 		return "", err
 	}
 
-	txHash, err := s.buildAndBroadcastCosmosTx(signer, []sdk.Msg{exchangeDetail})
+	txHash, err := s.buildAndBroadcastCosmosTx(ctx, signer, []sdk.Msg{exchangeDetail})
 	if err != nil {
-		return "", fmt.Errorf("create token failed, err: %v", err)
+		return "", fmt.Errorf("create token failed, err: %w", err)
 	}
 
 	return txHash, nil
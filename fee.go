@@ -0,0 +1,235 @@
+package gosdk
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	feemarketTypes "github.com/cysic-tech/gosdk/types/feemarket"
+
+	sdkmath "cosmossdk.io/math"
+	"github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkTx "github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+// FeeQuote is the price a transaction pays per unit of gas.
+type FeeQuote struct {
+	// GasPrice is the price per gas in the gas coin. For dynamic fee transactions it is the
+	// fee cap: the chain charges at most base fee plus tip, and never more than GasPrice.
+	GasPrice sdkmath.Int
+	// PriorityTip, when set, is attached as ExtensionOptionDynamicFeeTx.MaxPriorityPrice.
+	PriorityTip *sdkmath.Int
+}
+
+// FeeStrategy decides the gas price of a transaction.
+type FeeStrategy interface {
+	// Quote returns the gas price for the next transaction sent through s.
+	Quote(ctx context.Context, s *Server) (FeeQuote, error)
+}
+
+// feeQuote asks the fee strategy of the call, else of the Server, else the fixed gas price.
+//
+// @param ctx the context controlling cancellation and deadline
+// @param o the per-call options
+// @return the fee quote, or an error if the strategy fails
+func (s *Server) feeQuote(ctx context.Context, o *txOptions) (FeeQuote, error) {
+	strategy := o.feeStrategy
+	if strategy == nil {
+		strategy = s.feeStrategy
+	}
+	if strategy == nil {
+		strategy = FixedFee(sdkmath.NewInt(s.gas().price))
+	}
+
+	quote, err := strategy.Quote(ctx, s)
+	if err != nil {
+		s.log().Error("error when quote fee", "err", err)
+		return FeeQuote{}, err
+	}
+	if quote.GasPrice.IsNil() || quote.GasPrice.IsNegative() {
+		return FeeQuote{}, fmt.Errorf("fee strategy returned an invalid gas price")
+	}
+	if quote.PriorityTip != nil && (quote.PriorityTip.IsNil() || quote.PriorityTip.IsNegative()) {
+		return FeeQuote{}, fmt.Errorf("fee strategy returned an invalid priority tip")
+	}
+
+	return quote, nil
+}
+
+// GetBaseFee queries the EIP-1559 base fee from the fee market module.
+//
+// @return the base fee per gas, or nil if the chain has no base fee enabled, or an error if the query fails
+func (s *Server) GetBaseFee() (*sdkmath.Int, error) {
+	return s.GetBaseFeeCtx(context.Background())
+}
+
+// GetBaseFeeCtx is like GetBaseFee but honours ctx for cancellation and deadlines.
+func (s *Server) GetBaseFeeCtx(ctx context.Context) (*sdkmath.Int, error) {
+	if err := s.KeepGrpcConnCtx(ctx); err != nil {
+		s.log().Error("error when keep grpc conn", "endpoint", s.endPoint, "err", err)
+		return nil, err
+	}
+
+	client := feemarketTypes.NewQueryClient(s.conn())
+	resp, err := client.BaseFee(ctx, &feemarketTypes.QueryBaseFeeRequest{})
+	if err != nil {
+		s.log().Error("could not query base fee", "err", err)
+		return nil, wrapCtxErr(ctx, err)
+	}
+
+	return resp.BaseFee, nil
+}
+
+type fixedFee struct {
+	price sdkmath.Int
+}
+
+// FixedFee always pays price per gas, without the dynamic fee extension.
+//
+// @param price the gas price
+// @return the fee strategy
+func FixedFee(price sdkmath.Int) FeeStrategy {
+	return fixedFee{price: price}
+}
+
+func (f fixedFee) Quote(_ context.Context, _ *Server) (FeeQuote, error) {
+	return FeeQuote{GasPrice: f.price}, nil
+}
+
+type baseFeePlusTip struct {
+	tip        sdkmath.Int
+	multiplier int64
+}
+
+// BaseFeePlusTip builds dynamic fee transactions tipping tip per gas, with a fee cap of
+// multiplier times the current base fee plus the tip, so the transaction stays valid while
+// the base fee rises. Without a base fee on chain it falls back to the Server's gas price.
+//
+// @param tip the priority tip per gas
+// @param multiplier the base fee multiplier of the fee cap, 2 if less than 1
+// @return the fee strategy
+func BaseFeePlusTip(tip sdkmath.Int, multiplier int64) FeeStrategy {
+	if multiplier < 1 {
+		multiplier = 2
+	}
+
+	return baseFeePlusTip{tip: tip, multiplier: multiplier}
+}
+
+func (f baseFeePlusTip) Quote(ctx context.Context, s *Server) (FeeQuote, error) {
+	baseFee, err := s.GetBaseFeeCtx(ctx)
+	if err != nil {
+		return FeeQuote{}, err
+	}
+	if baseFee == nil {
+		return FeeQuote{GasPrice: sdkmath.NewInt(s.gas().price)}, nil
+	}
+
+	tip := f.tip
+	return FeeQuote{
+		GasPrice:    baseFee.MulRaw(f.multiplier).Add(tip),
+		PriorityTip: &tip,
+	}, nil
+}
+
+type percentileFee struct {
+	percentile float64
+	blocks     int64
+}
+
+// PercentileFee pays the given percentile of the gas prices paid in the last blocks, and at
+// least the base fee. Without transactions in those blocks it pays the base fee, or the
+// Server's gas price if the chain has none.
+//
+// @param percentile the percentile between 0 and 100
+// @param blocks the number of recent blocks to sample
+// @return the fee strategy
+func PercentileFee(percentile float64, blocks int64) FeeStrategy {
+	if percentile < 0 {
+		percentile = 0
+	}
+	if percentile > 100 {
+		percentile = 100
+	}
+	if blocks < 1 {
+		blocks = 1
+	}
+
+	return percentileFee{percentile: percentile, blocks: blocks}
+}
+
+func (f percentileFee) Quote(ctx context.Context, s *Server) (FeeQuote, error) {
+	price := sdkmath.NewInt(s.gas().price)
+
+	baseFee, err := s.GetBaseFeeCtx(ctx)
+	if err != nil {
+		return FeeQuote{}, err
+	}
+	if baseFee != nil {
+		price = *baseFee
+	}
+
+	prices, err := s.recentGasPrices(ctx, f.blocks)
+	if err != nil {
+		return FeeQuote{}, err
+	}
+	if len(prices) == 0 {
+		return FeeQuote{GasPrice: price}, nil
+	}
+
+	sort.Slice(prices, func(i, j int) bool { return prices[i].LT(prices[j]) })
+	index := int(f.percentile / 100 * float64(len(prices)-1))
+	sampled := prices[index].Ceil().RoundInt()
+	if baseFee == nil || sampled.GT(price) {
+		price = sampled
+	}
+
+	return FeeQuote{GasPrice: price}, nil
+}
+
+// recentGasPrices returns the gas price, in the Server's gas coin, of every transaction in the last blocks.
+//
+// @param ctx the context controlling cancellation and deadline
+// @param blocks the number of blocks to sample
+// @return the gas prices, or an error if a block can't be read
+func (s *Server) recentGasPrices(ctx context.Context, blocks int64) ([]sdk.Dec, error) {
+	if err := s.KeepGrpcConnCtx(ctx); err != nil {
+		s.log().Error("error when keep grpc conn", "endpoint", s.endPoint, "err", err)
+		return nil, err
+	}
+
+	client := tmservice.NewServiceClient(s.conn())
+	latest, err := client.GetLatestBlock(ctx, &tmservice.GetLatestBlockRequest{})
+	if err != nil {
+		s.log().Error("could not query latest block", "err", err)
+		return nil, wrapCtxErr(ctx, err)
+	}
+
+	gasCoin := s.gas().coin
+	height := latest.GetBlock().GetHeader().Height
+	prices := make([]sdk.Dec, 0)
+	for h := height; h > 0 && h > height-blocks; h-- {
+		resp, err := client.GetBlockByHeight(ctx, &tmservice.GetBlockByHeightRequest{Height: h})
+		if err != nil {
+			s.log().Error("could not query block", "height", h, "err", err)
+			return nil, wrapCtxErr(ctx, err)
+		}
+
+		for _, txBytes := range resp.GetBlock().GetData().Txs {
+			var raw sdkTx.TxRaw
+			if err := raw.Unmarshal(txBytes); err != nil {
+				continue
+			}
+			var authInfo sdkTx.AuthInfo
+			if err := authInfo.Unmarshal(raw.AuthInfoBytes); err != nil || authInfo.Fee == nil || authInfo.Fee.GasLimit == 0 {
+				continue
+			}
+
+			amount := authInfo.Fee.Amount.AmountOf(gasCoin)
+			prices = append(prices, sdk.NewDecFromInt(amount).QuoInt64(int64(authInfo.Fee.GasLimit)))
+		}
+	}
+
+	return prices, nil
+}
@@ -0,0 +1,95 @@
+package gosdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"testing"
+
+	feemarketTypes "github.com/cysic-tech/gosdk/types/feemarket"
+
+	sdkmath "cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// baseFeeFixture is a QueryBaseFeeResponse of a node with a base fee of 1 gwei: field 1,
+// the decimal string "1000000000".
+const baseFeeFixture = "0a0a31303030303030303030"
+
+func TestQueryBaseFeeResponseFixture(t *testing.T) {
+	fixture, err := hex.DecodeString(baseFeeFixture)
+	if err != nil {
+		t.Fatalf("decode fixture: %v", err)
+	}
+
+	var resp feemarketTypes.QueryBaseFeeResponse
+	if err := resp.Unmarshal(fixture); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if resp.BaseFee == nil || !resp.BaseFee.Equal(sdkmath.NewInt(1_000_000_000)) {
+		t.Fatalf("base fee = %v, want 1000000000", resp.BaseFee)
+	}
+
+	bz, err := resp.Marshal()
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !bytes.Equal(bz, fixture) {
+		t.Errorf("marshal = %x, want %v", bz, baseFeeFixture)
+	}
+	// the SDK encodes an Int field the same way
+	sdkBytes, err := (&sdk.IntProto{Int: sdkmath.NewInt(1_000_000_000)}).Marshal()
+	if err != nil {
+		t.Fatalf("marshal sdk int: %v", err)
+	}
+	if !bytes.Equal(sdkBytes, fixture) {
+		t.Errorf("sdk encoding = %x, want %v", sdkBytes, baseFeeFixture)
+	}
+
+	// a disabled base fee is an empty message
+	var disabled feemarketTypes.QueryBaseFeeResponse
+	if err := disabled.Unmarshal(nil); err != nil || disabled.BaseFee != nil {
+		t.Errorf("empty response: base fee %v, err %v; want nil", disabled.BaseFee, err)
+	}
+	if bz, err := disabled.Marshal(); err != nil || len(bz) != 0 {
+		t.Errorf("marshal empty response = %x, %v; want no bytes", bz, err)
+	}
+}
+
+func TestGetBaseFee(t *testing.T) {
+	chain := newMockChain()
+	node := chain.serve(t)
+	s := newTestServer(t, node, WithGas(CYSToken, 7))
+
+	baseFee, err := s.GetBaseFee()
+	if err != nil {
+		t.Fatalf("get base fee: %v", err)
+	}
+	if baseFee != nil {
+		t.Errorf("base fee = %v, want nil while disabled", baseFee)
+	}
+	quote, err := BaseFeePlusTip(sdkmath.NewInt(2), 2).Quote(context.Background(), s)
+	if err != nil {
+		t.Fatalf("quote: %v", err)
+	}
+	if !quote.GasPrice.Equal(sdkmath.NewInt(7)) || quote.PriorityTip != nil {
+		t.Errorf("quote without base fee = %+v, want the gas price 7", quote)
+	}
+
+	gwei := sdkmath.NewInt(1_000_000_000)
+	chain.setBaseFee(&gwei)
+	baseFee, err = s.GetBaseFee()
+	if err != nil {
+		t.Fatalf("get base fee: %v", err)
+	}
+	if baseFee == nil || !baseFee.Equal(gwei) {
+		t.Errorf("base fee = %v, want %v", baseFee, gwei)
+	}
+	quote, err = BaseFeePlusTip(sdkmath.NewInt(2), 2).Quote(context.Background(), s)
+	if err != nil {
+		t.Fatalf("quote: %v", err)
+	}
+	if !quote.GasPrice.Equal(sdkmath.NewInt(2_000_000_002)) || quote.PriorityTip == nil || !quote.PriorityTip.Equal(sdkmath.NewInt(2)) {
+		t.Errorf("quote = %+v, want twice the base fee plus a tip of 2", quote)
+	}
+}
@@ -0,0 +1,243 @@
+package gosdk
+
+import (
+	"context"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/cosmos/cosmos-sdk/x/feegrant"
+)
+
+// WithFeeGranter deducts the fee of one transaction from the allowance granter gave the signer
+// through x/feegrant, instead of from the signer's balance.
+//
+// @param granter the address of the account that granted the allowance
+func WithFeeGranter(granter sdk.AccAddress) TxOption {
+	return func(o *txOptions) {
+		o.feeGranter = granter
+	}
+}
+
+// GrantBasicAllowance lets grantee pay fees from the signer's balance, up to spendLimit.
+//
+// @param signer the TxSigner of the granter
+// @param grantee the address of the grantee
+// @param spendLimit the total amount grantee may spend, unlimited if empty
+// @param expiration the time the allowance expires, never if nil
+// @return the transaction hash as a string, or an error if the grant fails
+func (s *Server) GrantBasicAllowance(signer TxSigner, grantee string, spendLimit sdk.Coins, expiration *time.Time) (string, error) {
+	return s.GrantBasicAllowanceCtx(context.Background(), signer, grantee, spendLimit, expiration)
+}
+
+// GrantBasicAllowanceCtx is like GrantBasicAllowance but honours ctx for cancellation and deadlines.
+func (s *Server) GrantBasicAllowanceCtx(ctx context.Context, signer TxSigner, grantee string, spendLimit sdk.Coins, expiration *time.Time) (string, error) {
+	allowance := &feegrant.BasicAllowance{SpendLimit: spendLimit, Expiration: expiration}
+	return s.GrantAllowanceCtx(ctx, signer, grantee, allowance)
+}
+
+// GrantPeriodicAllowance lets grantee pay fees from the signer's balance, up to periodLimit in
+// every period and up to spendLimit in total. The first period starts now.
+//
+// @param signer the TxSigner of the granter
+// @param grantee the address of the grantee
+// @param spendLimit the total amount grantee may spend, unlimited if empty
+// @param expiration the time the allowance expires, never if nil
+// @param period the length of a period
+// @param periodLimit the amount grantee may spend in one period
+// @return the transaction hash as a string, or an error if the grant fails
+func (s *Server) GrantPeriodicAllowance(signer TxSigner, grantee string, spendLimit sdk.Coins, expiration *time.Time, period time.Duration, periodLimit sdk.Coins) (string, error) {
+	return s.GrantPeriodicAllowanceCtx(context.Background(), signer, grantee, spendLimit, expiration, period, periodLimit)
+}
+
+// GrantPeriodicAllowanceCtx is like GrantPeriodicAllowance but honours ctx for cancellation and deadlines.
+func (s *Server) GrantPeriodicAllowanceCtx(ctx context.Context, signer TxSigner, grantee string, spendLimit sdk.Coins, expiration *time.Time, period time.Duration, periodLimit sdk.Coins) (string, error) {
+	allowance := &feegrant.PeriodicAllowance{
+		Basic:            feegrant.BasicAllowance{SpendLimit: spendLimit, Expiration: expiration},
+		Period:           period,
+		PeriodSpendLimit: periodLimit,
+		PeriodCanSpend:   periodLimit,
+		PeriodReset:      time.Now().Add(period),
+	}
+	return s.GrantAllowanceCtx(ctx, signer, grantee, allowance)
+}
+
+// GrantAllowedMsgAllowance grants allowance restricted to transactions whose messages all
+// have one of the type URLs allowedMsgs, e.g. "/cosmos.bank.v1beta1.MsgSend".
+//
+// @param signer the TxSigner of the granter
+// @param grantee the address of the grantee
+// @param allowance the basic or periodic allowance to restrict
+// @param allowedMsgs the type URLs of the messages the allowance pays for
+// @return the transaction hash as a string, or an error if the grant fails
+func (s *Server) GrantAllowedMsgAllowance(signer TxSigner, grantee string, allowance feegrant.FeeAllowanceI, allowedMsgs []string) (string, error) {
+	return s.GrantAllowedMsgAllowanceCtx(context.Background(), signer, grantee, allowance, allowedMsgs)
+}
+
+// GrantAllowedMsgAllowanceCtx is like GrantAllowedMsgAllowance but honours ctx for cancellation and deadlines.
+func (s *Server) GrantAllowedMsgAllowanceCtx(ctx context.Context, signer TxSigner, grantee string, allowance feegrant.FeeAllowanceI, allowedMsgs []string) (string, error) {
+	filtered, err := feegrant.NewAllowedMsgAllowance(allowance, allowedMsgs)
+	if err != nil {
+		s.log().Error("error when create allowed msg allowance", "err", err)
+		return "", err
+	}
+
+	return s.GrantAllowanceCtx(ctx, signer, grantee, filtered)
+}
+
+// GrantAllowance grants grantee any fee allowance paid from the signer's balance.
+//
+// @param signer the TxSigner of the granter
+// @param grantee the address of the grantee
+// @param allowance the allowance
+// @return the transaction hash as a string, or an error if the grant fails
+func (s *Server) GrantAllowance(signer TxSigner, grantee string, allowance feegrant.FeeAllowanceI) (string, error) {
+	return s.GrantAllowanceCtx(context.Background(), signer, grantee, allowance)
+}
+
+// GrantAllowanceCtx is like GrantAllowance but honours ctx for cancellation and deadlines.
+func (s *Server) GrantAllowanceCtx(ctx context.Context, signer TxSigner, grantee string, allowance feegrant.FeeAllowanceI) (string, error) {
+	granteeAddr, err := toAccAddress(grantee)
+	if err != nil {
+		return "", err
+	}
+
+	msg, err := feegrant.NewMsgGrantAllowance(allowance, signer.Address(), granteeAddr)
+	if err != nil {
+		s.log().Error("error when create grant allowance msg", "err", err)
+		return "", err
+	}
+
+	return s.buildAndBroadcastCosmosTx(ctx, signer, []sdk.Msg{msg})
+}
+
+// RevokeAllowance revokes the allowance the signer granted to grantee.
+//
+// @param signer the TxSigner of the granter
+// @param grantee the address of the grantee
+// @return the transaction hash as a string, or an error if the revocation fails
+func (s *Server) RevokeAllowance(signer TxSigner, grantee string) (string, error) {
+	return s.RevokeAllowanceCtx(context.Background(), signer, grantee)
+}
+
+// RevokeAllowanceCtx is like RevokeAllowance but honours ctx for cancellation and deadlines.
+func (s *Server) RevokeAllowanceCtx(ctx context.Context, signer TxSigner, grantee string) (string, error) {
+	granteeAddr, err := toAccAddress(grantee)
+	if err != nil {
+		return "", err
+	}
+
+	msg := feegrant.NewMsgRevokeAllowance(signer.Address(), granteeAddr)
+	return s.buildAndBroadcastCosmosTx(ctx, signer, []sdk.Msg{&msg})
+}
+
+// QueryAllowance retrieves the allowance granter granted to grantee.
+//
+// @param granter the address of the granter
+// @param grantee the address of the grantee
+// @return the grant, or an error if there is none or the query fails
+func (s *Server) QueryAllowance(granter string, grantee string) (*feegrant.Grant, error) {
+	return s.QueryAllowanceCtx(context.Background(), granter, grantee)
+}
+
+// QueryAllowanceCtx is like QueryAllowance but honours ctx for cancellation and deadlines.
+func (s *Server) QueryAllowanceCtx(ctx context.Context, granter string, grantee string) (*feegrant.Grant, error) {
+	granterAddr, err := ConvertToCysicAddress(granter)
+	if err != nil {
+		s.log().Error("error when convert addr to cosmosAddr", "granter", granter, "err", err)
+		return nil, err
+	}
+	granteeAddr, err := ConvertToCysicAddress(grantee)
+	if err != nil {
+		s.log().Error("error when convert addr to cosmosAddr", "grantee", grantee, "err", err)
+		return nil, err
+	}
+
+	if err := s.KeepGrpcConnCtx(ctx); err != nil {
+		s.log().Error("error when keep grpc conn", "endpoint", s.endPoint, "err", err)
+		return nil, err
+	}
+
+	client := feegrant.NewQueryClient(s.conn())
+	resp, err := client.Allowance(ctx, &feegrant.QueryAllowanceRequest{Granter: granterAddr, Grantee: granteeAddr})
+	if err != nil {
+		s.log().Error("could not query allowance", "err", err)
+		return nil, wrapCtxErr(ctx, err)
+	}
+
+	return resp.Allowance, nil
+}
+
+// QueryAllowances retrieves the allowances granted to grantee with pagination.
+//
+// @param grantee the address of the grantee
+// @param offset the offset for pagination
+// @param pageSize the page size for pagination
+// @return a list of grants, the total count, or an error if the query fails
+func (s *Server) QueryAllowances(grantee string, offset uint64, pageSize uint64) ([]*feegrant.Grant, uint64, error) {
+	return s.QueryAllowancesCtx(context.Background(), grantee, offset, pageSize)
+}
+
+// QueryAllowancesCtx is like QueryAllowances but honours ctx for cancellation and deadlines.
+func (s *Server) QueryAllowancesCtx(ctx context.Context, grantee string, offset uint64, pageSize uint64) ([]*feegrant.Grant, uint64, error) {
+	granteeAddr, err := ConvertToCysicAddress(grantee)
+	if err != nil {
+		s.log().Error("error when convert addr to cosmosAddr", "grantee", grantee, "err", err)
+		return nil, 0, err
+	}
+
+	if err := s.KeepGrpcConnCtx(ctx); err != nil {
+		s.log().Error("error when keep grpc conn", "endpoint", s.endPoint, "err", err)
+		return nil, 0, err
+	}
+
+	client := feegrant.NewQueryClient(s.conn())
+	req := &feegrant.QueryAllowancesRequest{
+		Grantee:    granteeAddr,
+		Pagination: &query.PageRequest{Offset: offset, Limit: pageSize, CountTotal: true},
+	}
+	resp, err := client.Allowances(ctx, req)
+	if err != nil {
+		s.log().Error("could not query allowances", "err", err)
+		return nil, 0, wrapCtxErr(ctx, err)
+	}
+
+	return resp.Allowances, resp.Pagination.GetTotal(), nil
+}
+
+// QueryAllowancesByGranter retrieves the allowances granted by granter with pagination.
+//
+// @param granter the address of the granter
+// @param offset the offset for pagination
+// @param pageSize the page size for pagination
+// @return a list of grants, the total count, or an error if the query fails
+func (s *Server) QueryAllowancesByGranter(granter string, offset uint64, pageSize uint64) ([]*feegrant.Grant, uint64, error) {
+	return s.QueryAllowancesByGranterCtx(context.Background(), granter, offset, pageSize)
+}
+
+// QueryAllowancesByGranterCtx is like QueryAllowancesByGranter but honours ctx for cancellation and deadlines.
+func (s *Server) QueryAllowancesByGranterCtx(ctx context.Context, granter string, offset uint64, pageSize uint64) ([]*feegrant.Grant, uint64, error) {
+	granterAddr, err := ConvertToCysicAddress(granter)
+	if err != nil {
+		s.log().Error("error when convert addr to cosmosAddr", "granter", granter, "err", err)
+		return nil, 0, err
+	}
+
+	if err := s.KeepGrpcConnCtx(ctx); err != nil {
+		s.log().Error("error when keep grpc conn", "endpoint", s.endPoint, "err", err)
+		return nil, 0, err
+	}
+
+	client := feegrant.NewQueryClient(s.conn())
+	req := &feegrant.QueryAllowancesByGranterRequest{
+		Granter:    granterAddr,
+		Pagination: &query.PageRequest{Offset: offset, Limit: pageSize, CountTotal: true},
+	}
+	resp, err := client.AllowancesByGranter(ctx, req)
+	if err != nil {
+		s.log().Error("could not query allowances by granter", "err", err)
+		return nil, 0, wrapCtxErr(ctx, err)
+	}
+
+	return resp.Allowances, resp.Pagination.GetTotal(), nil
+}
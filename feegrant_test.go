@@ -0,0 +1,176 @@
+package gosdk
+
+import (
+	"testing"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkTx "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/feegrant"
+)
+
+func TestFeeGranterBytesToSign(t *testing.T) {
+	chain := newMockChain()
+	node := chain.serve(t)
+	s := newTestServer(t, node, WithGas(CYSToken, 1))
+
+	signer, granter := newTestSigner(t), newTestSigner(t)
+	msg, err := NewSendMsg(signer.Address().String(), newTestSigner(t).Address().String(), CYSToken, sdkmath.NewInt(1))
+	if err != nil {
+		t.Fatalf("new send msg: %v", err)
+	}
+
+	for name, tc := range map[string]struct {
+		opts    []TxOption
+		granter string
+	}{
+		"granter":    {[]TxOption{WithFeeGranter(granter.Address())}, granter.Address().String()},
+		"no granter": {nil, ""},
+	} {
+		txBuilder, signBytes, err := s.GetBytesToSign(signer, 0, 0, []sdk.Msg{msg}, tc.opts...)
+		if err != nil {
+			t.Fatalf("%v: bytes to sign: %v", name, err)
+		}
+
+		var signDoc sdkTx.SignDoc
+		if err := signDoc.Unmarshal(signBytes); err != nil {
+			t.Fatalf("%v: decode sign doc: %v", name, err)
+		}
+		var authInfo sdkTx.AuthInfo
+		if err := authInfo.Unmarshal(signDoc.AuthInfoBytes); err != nil {
+			t.Fatalf("%v: decode auth info: %v", name, err)
+		}
+		if authInfo.Fee.Granter != tc.granter {
+			t.Errorf("%v: signed fee granter %q, want %q", name, authInfo.Fee.Granter, tc.granter)
+		}
+		// the granter pays for the signer, who stays the fee payer
+		if authInfo.Fee.Payer != signer.Address().String() {
+			t.Errorf("%v: fee payer %q, want the signer %v", name, authInfo.Fee.Payer, signer.Address())
+		}
+		if got := txBuilder.GetTx().FeeGranter().String(); got != tc.granter {
+			t.Errorf("%v: tx fee granter %q, want %q", name, got, tc.granter)
+		}
+	}
+}
+
+func TestFeeGranterBroadcast(t *testing.T) {
+	chain := newMockChain()
+	node := chain.serve(t)
+	s := newTestServer(t, node, WithGas(CYSToken, 1))
+
+	signer, granter := newTestSigner(t), newTestSigner(t)
+	recipient := newTestSigner(t).Address()
+	chain.fund(signer.Address(), testCoins(100))
+	chain.fund(granter.Address(), testCoins(1_000_000))
+
+	msg, err := NewSendMsg(signer.Address().String(), recipient.String(), CYSToken, sdkmath.NewInt(100))
+	if err != nil {
+		t.Fatalf("new send msg: %v", err)
+	}
+	result, err := s.BroadcastMsgs(signer, []sdk.Msg{msg}, WithFeeGranter(granter.Address()))
+	if err != nil {
+		t.Fatalf("broadcast: %v", err)
+	}
+
+	if got := chain.includedTx(t, result.TxHash).FeeGranter(); !got.Equals(granter.Address()) {
+		t.Errorf("fee granter = %v, want %v", got, granter.Address())
+	}
+	// the granter paid the fee without signing
+	if got := chain.account(signer.Address()).balance; !got.IsZero() {
+		t.Errorf("signer balance = %v, want none left", got)
+	}
+	if got := chain.account(granter.Address()); !got.balance.IsAllLT(testCoins(1_000_000)) || got.sequence != 0 {
+		t.Errorf("granter = %+v, want the fee deducted and sequence 0", got)
+	}
+}
+
+func TestGrantAllowances(t *testing.T) {
+	chain := newMockChain()
+	node := chain.serve(t)
+	s := newTestServer(t, node, WithGas(CYSToken, 1))
+
+	granter, grantee := newTestSigner(t), newTestSigner(t).Address()
+	chain.fund(granter.Address(), testCoins(1_000_000))
+
+	spendLimit, periodLimit := testCoins(1_000), testCoins(100)
+	expiration := time.Now().Add(30 * 24 * time.Hour).UTC().Truncate(time.Second)
+	granted := func(txHash string) *feegrant.MsgGrantAllowance {
+		t.Helper()
+
+		msgs := chain.includedTx(t, txHash).GetMsgs()
+		if len(msgs) != 1 {
+			t.Fatalf("%d msgs, want 1", len(msgs))
+		}
+		msg, ok := msgs[0].(*feegrant.MsgGrantAllowance)
+		if !ok {
+			t.Fatalf("msg is a %T, want a MsgGrantAllowance", msgs[0])
+		}
+		if msg.Granter != granter.Address().String() || msg.Grantee != grantee.String() {
+			t.Errorf("grant from %v to %v, want from %v to %v", msg.Granter, msg.Grantee, granter.Address(), grantee)
+		}
+		if err := msg.ValidateBasic(); err != nil {
+			t.Errorf("invalid grant: %v", err)
+		}
+		return msg
+	}
+
+	before := time.Now()
+	txHash, err := s.GrantPeriodicAllowance(granter, grantee.String(), spendLimit, &expiration, time.Hour, periodLimit)
+	if err != nil {
+		t.Fatalf("grant periodic allowance: %v", err)
+	}
+	allowance, err := granted(txHash).GetFeeAllowanceI()
+	if err != nil {
+		t.Fatalf("unpack allowance: %v", err)
+	}
+	periodic, ok := allowance.(*feegrant.PeriodicAllowance)
+	if !ok {
+		t.Fatalf("allowance is a %T, want a PeriodicAllowance", allowance)
+	}
+	if !periodic.Basic.SpendLimit.IsEqual(spendLimit) || !periodic.Basic.Expiration.Equal(expiration) {
+		t.Errorf("basic allowance = %+v, want limit %v until %v", periodic.Basic, spendLimit, expiration)
+	}
+	if periodic.Period != time.Hour || !periodic.PeriodSpendLimit.IsEqual(periodLimit) || !periodic.PeriodCanSpend.IsEqual(periodLimit) {
+		t.Errorf("periodic allowance = %+v, want %v per hour", periodic, periodLimit)
+	}
+	if reset := periodic.PeriodReset; reset.Before(before.Add(time.Hour)) || reset.After(time.Now().Add(time.Hour)) {
+		t.Errorf("period reset = %v, want an hour from now", reset)
+	}
+
+	allowedMsgs := []string{"/cosmos.bank.v1beta1.MsgSend"}
+	txHash, err = s.GrantAllowedMsgAllowance(granter, grantee.String(), periodic, allowedMsgs)
+	if err != nil {
+		t.Fatalf("grant allowed msg allowance: %v", err)
+	}
+	allowance, err = granted(txHash).GetFeeAllowanceI()
+	if err != nil {
+		t.Fatalf("unpack allowance: %v", err)
+	}
+	filtered, ok := allowance.(*feegrant.AllowedMsgAllowance)
+	if !ok {
+		t.Fatalf("allowance is a %T, want an AllowedMsgAllowance", allowance)
+	}
+	if len(filtered.AllowedMessages) != 1 || filtered.AllowedMessages[0] != allowedMsgs[0] {
+		t.Errorf("allowed messages = %v, want %v", filtered.AllowedMessages, allowedMsgs)
+	}
+	inner, err := filtered.GetAllowance()
+	if err != nil {
+		t.Fatalf("unpack inner allowance: %v", err)
+	}
+	if innerPeriodic, ok := inner.(*feegrant.PeriodicAllowance); !ok || innerPeriodic.Period != time.Hour {
+		t.Errorf("inner allowance = %+v, want the periodic allowance", inner)
+	}
+
+	txHash, err = s.GrantBasicAllowance(granter, grantee.String(), nil, nil)
+	if err != nil {
+		t.Fatalf("grant basic allowance: %v", err)
+	}
+	allowance, err = granted(txHash).GetFeeAllowanceI()
+	if err != nil {
+		t.Fatalf("unpack allowance: %v", err)
+	}
+	if basic, ok := allowance.(*feegrant.BasicAllowance); !ok || !basic.SpendLimit.Empty() || basic.Expiration != nil {
+		t.Errorf("allowance = %+v, want an unlimited basic allowance", allowance)
+	}
+}
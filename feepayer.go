@@ -0,0 +1,72 @@
+package gosdk
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+)
+
+// signerAccount is a TxSigner with the account number and sequence it signs a transaction with.
+type signerAccount struct {
+	signer    TxSigner
+	accNumber uint64
+	sequence  uint64
+}
+
+// WithFeePayer makes sponsor pay the fee of one transaction. The transaction is signed by the
+// signer of its messages and co-signed by sponsor, which is set as fee payer; the signer needs
+// no balance of the gas coin.
+//
+// Both signatures are ordinary Cosmos signatures in SignModeDirect. SignModeEIP712 transactions
+// can't have a fee payer: the Ethermint ante handler verifies a Web3 transaction against a single
+// signer, which pays its own fee, so they fail with an error.
+//
+// @param sponsor the TxSigner paying the fee; ignored if it is the signer itself
+func WithFeePayer(sponsor TxSigner) TxOption {
+	return func(o *txOptions) {
+		o.feePayer = &signerAccount{signer: sponsor}
+	}
+}
+
+// resolveFeePayer fills in the account number and sequence of the fee payer of o, if any.
+//
+// @param ctx the context controlling cancellation and deadline
+// @param conn the connection to query the account through
+// @param o the options of the transaction
+// @param seqs the locked local sequence state of the fee payer, or nil without a SequenceManager
+// @param useNonce whether the fee payer's Nonce may raise its on-chain sequence
+// @return an error if the fee payer account can't be read or the transaction is signed in SignModeEIP712
+func (s *Server) resolveFeePayer(ctx context.Context, conn grpc.ClientConnInterface, o *txOptions, seqs *accountSequence, useNonce bool) error {
+	if o.feePayer == nil {
+		return nil
+	}
+	if o.signMode == SignModeEIP712 {
+		return fmt.Errorf("fee payer %v can't sponsor a transaction in sign mode %v", o.feePayer.signer.Address().String(), o.signMode)
+	}
+
+	accNumber, sequence, err := s.nextSequence(ctx, conn, o.feePayer.signer, seqs, useNonce)
+	if err != nil {
+		return err
+	}
+	o.feePayer.accNumber, o.feePayer.sequence = accNumber, sequence
+
+	return nil
+}
+
+// dropSelfFeePayer removes a fee payer that is signer itself, which pays its fee anyway.
+func (o *txOptions) dropSelfFeePayer(signer TxSigner) {
+	if o.feePayer != nil && o.feePayer.signer.Address().Equals(signer.Address()) {
+		o.feePayer = nil
+	}
+}
+
+// accounts returns the addresses whose sequences a transaction of signer uses: signer's and the fee payer's.
+func (o *txOptions) accounts(signer TxSigner) []string {
+	addresses := []string{signer.Address().String()}
+	if o.feePayer != nil {
+		addresses = append(addresses, o.feePayer.signer.Address().String())
+	}
+
+	return addresses
+}
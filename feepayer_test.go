@@ -0,0 +1,83 @@
+package gosdk
+
+import (
+	"strings"
+	"testing"
+
+	sdkmath "cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestFeePayerSponsorsDirectTx(t *testing.T) {
+	chain := newMockChain()
+	node := chain.serve(t)
+	s := newTestServer(t, node, WithGas(CYSToken, 1))
+
+	signer, sponsor := newTestSigner(t), newTestSigner(t)
+	recipient := newTestSigner(t).Address()
+	chain.fund(signer.Address(), testCoins(100))
+	chain.fund(sponsor.Address(), testCoins(1_000_000))
+	chain.setSequence(sponsor.Address(), 5)
+
+	msg, err := NewSendMsg(signer.Address().String(), recipient.String(), CYSToken, sdkmath.NewInt(100))
+	if err != nil {
+		t.Fatalf("new send msg: %v", err)
+	}
+	result, err := s.BroadcastMsgs(signer, []sdk.Msg{msg}, WithFeePayer(sponsor))
+	if err != nil {
+		t.Fatalf("sponsored broadcast: %v", err)
+	}
+	if result.Sequence != 0 {
+		t.Errorf("sequence = %d, want the signer's 0", result.Sequence)
+	}
+
+	// the signer spent all its coins on the transfer, the sponsor paid the fee
+	if got := chain.account(signer.Address()).balance; !got.IsZero() {
+		t.Errorf("signer balance = %v, want none left", got)
+	}
+	if got := chain.account(recipient).balance; !got.IsEqual(testCoins(100)) {
+		t.Errorf("recipient balance = %v, want %v", got, testCoins(100))
+	}
+	if got := chain.account(sponsor.Address()).balance; !got.IsAllLT(testCoins(1_000_000)) {
+		t.Errorf("sponsor balance = %v, want the fee deducted", got)
+	}
+	if got := chain.account(signer.Address()).sequence; got != 1 {
+		t.Errorf("signer sequence = %d, want 1", got)
+	}
+	if got := chain.account(sponsor.Address()).sequence; got != 6 {
+		t.Errorf("sponsor sequence = %d, want 6", got)
+	}
+}
+
+func TestFeePayerRejectsEIP712(t *testing.T) {
+	chain := newMockChain()
+	node := chain.serve(t)
+	s := newTestServer(t, node)
+
+	signer, sponsor := newTestSigner(t), newTestSigner(t)
+	chain.fund(signer.Address(), testCoins(100))
+	chain.fund(sponsor.Address(), testCoins(1_000_000))
+	msg, err := NewSendMsg(signer.Address().String(), newTestSigner(t).Address().String(), CYSToken, sdkmath.NewInt(1))
+	if err != nil {
+		t.Fatalf("new send msg: %v", err)
+	}
+
+	opts := []TxOption{WithFeePayer(sponsor), WithTxSignMode(SignModeEIP712)}
+	if _, err := s.BroadcastMsgs(signer, []sdk.Msg{msg}, opts...); err == nil || !strings.Contains(err.Error(), "can't sponsor") {
+		t.Errorf("broadcast: got %v, want a fee payer error", err)
+	}
+	if _, err := s.EstimateGas(signer, []sdk.Msg{msg}, opts...); err == nil || !strings.Contains(err.Error(), "can't sponsor") {
+		t.Errorf("estimate gas: got %v, want a fee payer error", err)
+	}
+	if _, _, err := s.GetBytesToSign(signer, 0, 0, []sdk.Msg{msg}, opts...); err == nil || !strings.Contains(err.Error(), "can't sponsor") {
+		t.Errorf("bytes to sign: got %v, want a fee payer error", err)
+	}
+	if n := node.callCount(broadcastMethod); n != 0 {
+		t.Errorf("%d broadcasts, want none", n)
+	}
+
+	// a signer paying its own fee may sign in EIP-712
+	if _, _, err := s.GetBytesToSign(signer, 0, 0, []sdk.Msg{msg}, WithFeePayer(signer), WithTxSignMode(SignModeEIP712)); err != nil {
+		t.Errorf("bytes to sign with the signer as fee payer: %v", err)
+	}
+}
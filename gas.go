@@ -0,0 +1,171 @@
+package gosdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	sdkmath "cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkTx "github.com/cosmos/cosmos-sdk/types/tx"
+	"google.golang.org/grpc"
+)
+
+// defaultGasAdjustment is the margin applied on top of the simulated gas usage.
+const defaultGasAdjustment = 1.3
+
+// GasEstimate is the result of simulating a transaction.
+type GasEstimate struct {
+	// GasUsed is the gas the simulation consumed, zero when simulation is disabled.
+	GasUsed uint64
+	// GasLimit is the gas limit the transaction is signed with: GasUsed times the gas
+	// adjustment, bounded by the gas floor and ceiling.
+	GasLimit uint64
+	// Fee is the fee charged for GasLimit at the gas price of the fee strategy.
+	Fee sdk.Coins
+}
+
+// EstimateGas simulates msgList signed by signer and returns the gas it needs and the resulting fee.
+//
+// @param signer the TxSigner the transaction is for
+// @param msgList the messages of the transaction
+// @param opts the options the transaction would be sent with
+// @return the gas estimate, or an error if the simulation fails
+func (s *Server) EstimateGas(signer TxSigner, msgList []sdk.Msg, opts ...TxOption) (*GasEstimate, error) {
+	return s.EstimateGasCtx(context.Background(), signer, msgList, opts...)
+}
+
+// EstimateGasCtx is like EstimateGas but honours ctx for cancellation and deadlines.
+func (s *Server) EstimateGasCtx(ctx context.Context, signer TxSigner, msgList []sdk.Msg, opts ...TxOption) (*GasEstimate, error) {
+	if err := s.KeepGrpcConnCtx(ctx); err != nil {
+		s.log().Error("error when keep grpc conn", "endpoint", s.endPoint, "err", err)
+		return nil, err
+	}
+
+	conn := s.accountConn(signer.Address().String())
+
+	txOpts := s.newTxOptions(opts)
+	txOpts.dropSelfFeePayer(signer)
+	seqStates, unlock := s.sequences.lock(txOpts.accounts(signer)...)
+	defer unlock()
+
+	_, sequence, err := s.nextSequence(ctx, conn, signer, seqStates[0], true)
+	if err != nil {
+		return nil, err
+	}
+	if txOpts.feePayer != nil {
+		if err := s.resolveFeePayer(ctx, conn, txOpts, seqStates[1], true); err != nil {
+			return nil, err
+		}
+	}
+
+	quote, err := s.feeQuote(ctx, txOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	gasUsed, err := s.simulate(ctx, conn, signer, sequence, msgList, quote, txOpts)
+	if err != nil {
+		if errors.Is(err, ErrSequenceMismatch) {
+			sequenceMismatch(seqStates, txOpts, err)
+		}
+		return nil, err
+	}
+
+	return s.gasEstimateFor(gasUsed, quote)
+}
+
+// estimateGas returns the gas limit the transaction pipeline signs with: the simulated usage
+// when auto gas is enabled, the fixed gas limit otherwise.
+//
+// @param ctx the context controlling cancellation and deadline
+// @param conn the connection to simulate through
+// @param signer the TxSigner the transaction is for
+// @param sequence the sequence the transaction will be signed with
+// @param msgList the messages of the transaction
+// @param quote the gas price of the transaction
+// @param o the options of the transaction
+// @return the gas estimate, or an error if the simulation fails
+func (s *Server) estimateGas(ctx context.Context, conn grpc.ClientConnInterface, signer TxSigner, sequence uint64, msgList []sdk.Msg, quote FeeQuote, o *txOptions) (*GasEstimate, error) {
+	if !s.autoGas {
+		limit := s.gas().limit
+		return &GasEstimate{GasLimit: limit, Fee: s.feeFor(limit, quote)}, nil
+	}
+
+	gasUsed, err := s.simulate(ctx, conn, signer, sequence, msgList, quote, o)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.gasEstimateFor(gasUsed, quote)
+}
+
+// gasEstimateFor applies the gas adjustment and bounds to a simulated gas usage.
+//
+// @param gasUsed the simulated gas usage
+// @param quote the gas price the fee is computed at
+// @return the gas estimate, or an error if the adjusted gas exceeds the ceiling
+func (s *Server) gasEstimateFor(gasUsed uint64, quote FeeQuote) (*GasEstimate, error) {
+	limit := uint64(math.Ceil(float64(gasUsed) * s.gasAdjustment))
+	if limit < s.gasFloor {
+		limit = s.gasFloor
+	}
+
+	ceiling := s.gasCeiling
+	if ceiling == 0 {
+		ceiling = s.gas().limit
+	}
+	if limit > ceiling {
+		return nil, fmt.Errorf("estimated gas %d exceeds gas ceiling %d", limit, ceiling)
+	}
+
+	return &GasEstimate{GasUsed: gasUsed, GasLimit: limit, Fee: s.feeFor(limit, quote)}, nil
+}
+
+// simulate runs the transaction through the Simulate RPC without a signature and without a
+// fee, so that simulating never fails for lack of funds to pay the ceiling fee.
+//
+// @param ctx the context controlling cancellation and deadline
+// @param conn the connection to simulate through
+// @param signer the TxSigner the transaction is for
+// @param sequence the sequence the transaction will be signed with
+// @param msgList the messages of the transaction
+// @param quote the gas price of the transaction; only its extension options are simulated
+// @param o the options of the transaction
+// @return the gas used by the simulation, or an error if the transaction would fail; a
+// rejection by the chain is a *TxError, as for a broadcast
+func (s *Server) simulate(ctx context.Context, conn grpc.ClientConnInterface, signer TxSigner, sequence uint64, msgList []sdk.Msg, quote FeeQuote, o *txOptions) (uint64, error) {
+	txBuilder, err := s.newTxBuilder(signer, sequence, msgList, s.gas().limit, quote, o)
+	if err != nil {
+		return 0, err
+	}
+	txBuilder.SetFeeAmount(sdk.Coins{})
+
+	txBytes, err := txConfig.TxEncoder()(txBuilder.GetTx())
+	if err != nil {
+		s.log().Error("error when encode simulate tx", "err", err)
+		return 0, err
+	}
+
+	client := sdkTx.NewServiceClient(conn)
+	resp, err := client.Simulate(ctx, &sdkTx.SimulateRequest{TxBytes: txBytes})
+	if err != nil {
+		s.log().Error("error when simulate tx", "err", err)
+		return 0, wrapCtxErr(ctx, simulateError(err))
+	}
+
+	return resp.GasInfo.GasUsed, nil
+}
+
+// feeFor returns the fee for gasLimit at the quoted gas price.
+//
+// @param gasLimit the gas limit of the transaction
+// @param quote the gas price of the transaction
+// @return the fee
+func (s *Server) feeFor(gasLimit uint64, quote FeeQuote) sdk.Coins {
+	return sdk.Coins{sdk.NewCoin(
+		s.gas().coin,
+		quote.GasPrice.Mul(sdkmath.NewIntFromUint64(gasLimit)),
+	)}
+}
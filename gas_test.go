@@ -0,0 +1,75 @@
+package gosdk
+
+import (
+	"errors"
+	"testing"
+
+	sdkmath "cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestBroadcastRecoversStaleSequenceInSimulation(t *testing.T) {
+	chain := newMockChain()
+	node := chain.serve(t)
+	s := newTestServer(t, node, WithLocalSequences())
+
+	signer := newTestSigner(t)
+	chain.fund(signer.Address(), testCoins(1_000))
+	msg, err := NewSendMsg(signer.Address().String(), newTestSigner(t).Address().String(), CYSToken, sdkmath.NewInt(1))
+	if err != nil {
+		t.Fatalf("new send msg: %v", err)
+	}
+
+	if _, err := s.BroadcastMsgs(signer, []sdk.Msg{msg}); err != nil {
+		t.Fatalf("first broadcast: %v", err)
+	}
+
+	// another process sends from the account, the local sequence 1 is now stale
+	chain.setSequence(signer.Address(), 4)
+
+	result, err := s.BroadcastMsgs(signer, []sdk.Msg{msg})
+	if err != nil {
+		t.Fatalf("broadcast with stale sequence: %v", err)
+	}
+	if result.Sequence != 4 {
+		t.Errorf("sequence = %d, want 4", result.Sequence)
+	}
+	if got := chain.account(signer.Address()).sequence; got != 5 {
+		t.Errorf("chain sequence = %d, want 5", got)
+	}
+	if node.callCount("/cosmos.tx.v1beta1.Service/BroadcastTx") != 2 {
+		t.Errorf("stale sequence was broadcast, want it caught in simulation")
+	}
+}
+
+func TestEstimateGasSequenceMismatch(t *testing.T) {
+	chain := newMockChain()
+	node := chain.serve(t)
+	s := newTestServer(t, node, WithLocalSequences())
+
+	signer := newTestSigner(t)
+	chain.fund(signer.Address(), testCoins(1_000))
+	msg, err := NewSendMsg(signer.Address().String(), newTestSigner(t).Address().String(), CYSToken, sdkmath.NewInt(1))
+	if err != nil {
+		t.Fatalf("new send msg: %v", err)
+	}
+
+	if _, err := s.EstimateGas(signer, []sdk.Msg{msg}); err != nil {
+		t.Fatalf("estimate gas: %v", err)
+	}
+	chain.setSequence(signer.Address(), 7)
+
+	_, err = s.EstimateGas(signer, []sdk.Msg{msg})
+	var txErr *TxError
+	if !errors.As(err, &txErr) {
+		t.Fatalf("estimate gas with stale sequence: got %v, want a *TxError", err)
+	}
+	if !errors.Is(err, ErrSequenceMismatch) || !IsRetryableError(err) {
+		t.Errorf("error %v: want a retryable ErrSequenceMismatch", err)
+	}
+
+	// the expected sequence was adopted from the log
+	if _, err := s.EstimateGas(signer, []sdk.Msg{msg}); err != nil {
+		t.Fatalf("estimate gas after mismatch: %v", err)
+	}
+}
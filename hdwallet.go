@@ -0,0 +1,157 @@
+package gosdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	etherminthd "github.com/cysic-tech/gosdk/crypto/hd"
+	cysicTypes "github.com/cysic-tech/gosdk/types/cysic"
+
+	bip39 "github.com/tyler-smith/go-bip39"
+)
+
+// defaultGapLimit is the number of consecutive unused accounts after which ScanAccounts stops,
+// as in BIP-44 account discovery.
+const defaultGapLimit = 20
+
+// DerivedSigner is a Signer derived from a mnemonic, with the HD path it was derived at.
+type DerivedSigner struct {
+	// Index is the position of the path in the iteration, starting at 0 for the base path.
+	Index int
+	// Path is the HD path of the key, e.g. "m/44'/60'/0'/0/3".
+	Path   string
+	Signer *Signer
+}
+
+// GenerateMnemonic generates a new BIP-39 mnemonic.
+//
+// @param bits the entropy size, a multiple of 32 between 128 (12 words) and 256 (24 words)
+// @return the mnemonic, or an error if bits is invalid
+func GenerateMnemonic(bits int) (string, error) {
+	entropy, err := bip39.NewEntropy(bits)
+	if err != nil {
+		logger().Error("error when new entropy", "bits", bits, "err", err)
+		return "", err
+	}
+
+	return bip39.NewMnemonic(entropy)
+}
+
+// DeriveSigners derives n eth_secp256k1 Signers from a mnemonic by walking the HD path iterator
+// of basePath: the default iterator increments the last path component (m/44'/60'/0'/0/0,
+// .../0/1, ...), the Ledger Live one the account (m/44'/60'/0'/0/0, m/44'/60'/1'/0/0, ...).
+//
+// @param mnemonic the mnemonic phrase
+// @param passphrase the BIP-39 passphrase, empty for none
+// @param basePath the first HD path, BIP44HDPath if empty
+// @param n the number of Signers to derive
+// @param ledgerLiveStyle whether to iterate over accounts as Ledger Live does
+// @return the derived Signers in path order, or an error if n is negative or the mnemonic or path is invalid
+func DeriveSigners(mnemonic string, passphrase string, basePath string, n int, ledgerLiveStyle bool) ([]DerivedSigner, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("invalid number of signers %d", n)
+	}
+
+	next, err := newDeriver(mnemonic, passphrase, basePath, ledgerLiveStyle)
+	if err != nil {
+		return nil, err
+	}
+
+	signers := make([]DerivedSigner, 0, n)
+	for i := 0; i < n; i++ {
+		derived, err := next()
+		if err != nil {
+			return nil, err
+		}
+		signers = append(signers, derived)
+	}
+
+	return signers, nil
+}
+
+// newDeriver returns a function deriving the Signer of the next path of the iterator.
+func newDeriver(mnemonic string, passphrase string, basePath string, ledgerLiveStyle bool) (func() (DerivedSigner, error), error) {
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, fmt.Errorf("invalid mnemonic")
+	}
+	if basePath == "" {
+		basePath = cysicTypes.BIP44HDPath
+	}
+
+	iterator, err := cysicTypes.NewHDPathIterator(basePath, ledgerLiveStyle)
+	if err != nil {
+		logger().Error("error when new hd path iterator", "path", basePath, "err", err)
+		return nil, err
+	}
+
+	index := 0
+	return func() (DerivedSigner, error) {
+		path := iterator().String()
+		derivedPriv, err := etherminthd.EthSecp256k1.Derive()(mnemonic, passphrase, path)
+		if err != nil {
+			logger().Error("error when get derive private key", "path", path, "err", err)
+			return DerivedSigner{}, err
+		}
+
+		derived := DerivedSigner{
+			Index:  index,
+			Path:   path,
+			Signer: newSignerWithPrivKey(etherminthd.EthSecp256k1.Generate()(derivedPriv)),
+		}
+		index++
+		return derived, nil
+	}, nil
+}
+
+// ScanAccounts recovers the accounts of a mnemonic: it derives Signers as DeriveSigners does and
+// keeps those whose account exists on chain, until gapLimit consecutive paths have no account.
+//
+// @param mnemonic the mnemonic phrase
+// @param passphrase the BIP-39 passphrase, empty for none
+// @param basePath the first HD path, BIP44HDPath if empty
+// @param ledgerLiveStyle whether to iterate over accounts as Ledger Live does
+// @param gapLimit the number of consecutive unused paths to stop after, 20 if not positive
+// @return the Signers of the used accounts, or an error if an account query fails
+func (s *Server) ScanAccounts(mnemonic string, passphrase string, basePath string, ledgerLiveStyle bool, gapLimit int) ([]DerivedSigner, error) {
+	return s.ScanAccountsCtx(context.Background(), mnemonic, passphrase, basePath, ledgerLiveStyle, gapLimit)
+}
+
+// ScanAccountsCtx is like ScanAccounts but honours ctx for cancellation and deadlines.
+func (s *Server) ScanAccountsCtx(ctx context.Context, mnemonic string, passphrase string, basePath string, ledgerLiveStyle bool, gapLimit int) ([]DerivedSigner, error) {
+	if gapLimit <= 0 {
+		gapLimit = defaultGapLimit
+	}
+
+	next, err := newDeriver(mnemonic, passphrase, basePath, ledgerLiveStyle)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.KeepGrpcConnCtx(ctx); err != nil {
+		s.log().Error("error when keep grpc conn", "endpoint", s.endPoint, "err", err)
+		return nil, err
+	}
+
+	var used []DerivedSigner
+	for gap := 0; gap < gapLimit; {
+		derived, err := next()
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = s.getAccountByAddr(ctx, s.conn(), derived.Signer.CosmosAddr.String())
+		switch {
+		case err == nil:
+			used = append(used, derived)
+			gap = 0
+		case errors.Is(err, ErrAccountNotFound):
+			gap++
+		default:
+			s.log().Error("error when scan account", "path", derived.Path, "err", err)
+			return nil, err
+		}
+	}
+
+	return used, nil
+}
@@ -0,0 +1,117 @@
+package gosdk
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const accountMethod = "/cosmos.auth.v1beta1.Query/Account"
+
+// testMnemonic is the development mnemonic of Hardhat and Anvil, whose first accounts at
+// m/44'/60'/0'/0/i are well known.
+const testMnemonic = "test test test test test test test test test test test junk"
+
+var testMnemonicAddresses = []string{
+	"0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
+	"0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+	"0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC",
+}
+
+func TestDeriveSignersBIP44Vector(t *testing.T) {
+	signers, err := DeriveSigners(testMnemonic, "", "", len(testMnemonicAddresses), false)
+	if err != nil {
+		t.Fatalf("derive signers: %v", err)
+	}
+	if len(signers) != len(testMnemonicAddresses) {
+		t.Fatalf("derived %d signers, want %d", len(signers), len(testMnemonicAddresses))
+	}
+
+	for i, derived := range signers {
+		if want := fmt.Sprintf("m/44'/60'/0'/0/%d", i); derived.Path != want || derived.Index != i {
+			t.Errorf("signer %d: index %d at %v, want %v", i, derived.Index, derived.Path, want)
+		}
+		if want := common.HexToAddress(testMnemonicAddresses[i]); derived.Signer.EthAddr != want {
+			t.Errorf("signer %d: address %v, want %v", i, derived.Signer.EthAddr, want)
+		}
+	}
+}
+
+func TestDeriveSignersLedgerLive(t *testing.T) {
+	signers, err := DeriveSigners(testMnemonic, "", "", 3, true)
+	if err != nil {
+		t.Fatalf("derive signers: %v", err)
+	}
+
+	for i, derived := range signers {
+		want := fmt.Sprintf("m/44'/60'/%d'/0/0", i)
+		if derived.Path != want {
+			t.Errorf("signer %d: path %v, want %v", i, derived.Path, want)
+		}
+		single, err := NewSignerWithMnemonic(testMnemonic, "", want, "eth_secp256k1")
+		if err != nil {
+			t.Fatalf("new signer at %v: %v", want, err)
+		}
+		if !derived.Signer.Address().Equals(single.Address()) {
+			t.Errorf("signer %d: address %v, want %v", i, derived.Signer.Address(), single.Address())
+		}
+	}
+	// both layouts start at the base path
+	if signers[0].Signer.EthAddr != common.HexToAddress(testMnemonicAddresses[0]) {
+		t.Errorf("first Ledger Live signer %v, want %v", signers[0].Signer.EthAddr, testMnemonicAddresses[0])
+	}
+}
+
+func TestDeriveSignersInvalid(t *testing.T) {
+	if _, err := DeriveSigners(testMnemonic, "", "", -1, false); err == nil {
+		t.Error("negative count: got no error")
+	}
+	if signers, err := DeriveSigners(testMnemonic, "", "", 0, false); err != nil || len(signers) != 0 {
+		t.Errorf("no signers: got %d signers, %v", len(signers), err)
+	}
+	if _, err := DeriveSigners("test test test", "", "", 1, false); err == nil || !strings.Contains(err.Error(), "invalid mnemonic") {
+		t.Errorf("invalid mnemonic: got %v", err)
+	}
+	if _, err := DeriveSigners(testMnemonic, "", "not a path", 1, false); err == nil {
+		t.Error("invalid path: got no error")
+	}
+}
+
+func TestScanAccounts(t *testing.T) {
+	chain := newMockChain()
+	node := chain.serve(t)
+	s := newTestServer(t, node)
+
+	signers, err := DeriveSigners(testMnemonic, "", "", 6, false)
+	if err != nil {
+		t.Fatalf("derive signers: %v", err)
+	}
+	for _, i := range []int{0, 2, 5} {
+		chain.fund(signers[i].Signer.Address(), testCoins(1))
+	}
+
+	// the gap of two unused paths after index 2 hides index 5
+	used, err := s.ScanAccounts(testMnemonic, "", "", false, 2)
+	if err != nil {
+		t.Fatalf("scan accounts: %v", err)
+	}
+	if len(used) != 2 || used[0].Index != 0 || used[1].Index != 2 {
+		t.Errorf("used accounts = %+v, want indexes 0 and 2", used)
+	}
+	if n := node.callCount(accountMethod); n != 5 {
+		t.Errorf("%d account queries, want 5", n)
+	}
+
+	used, err = s.ScanAccounts(testMnemonic, "", "", false, 3)
+	if err != nil {
+		t.Fatalf("scan accounts: %v", err)
+	}
+	if len(used) != 3 || used[2].Index != 5 || !used[2].Signer.Address().Equals(signers[5].Signer.Address()) {
+		t.Errorf("used accounts = %+v, want indexes 0, 2 and 5", used)
+	}
+	if n := node.callCount(accountMethod); n != 5+9 {
+		t.Errorf("%d account queries, want 14", n)
+	}
+}
@@ -1,11 +1,67 @@
 package gosdk
 
 import (
+	"github.com/cysic-tech/gosdk/crypto/ethsecp256k1"
+	"github.com/cysic-tech/gosdk/ethereum/eip712"
+	cysicTypes "github.com/cysic-tech/gosdk/types/cysic"
+	delegateTypes "github.com/cysic-tech/gosdk/types/delegate"
+	govTokenTypes "github.com/cysic-tech/gosdk/types/govtoken"
+
+	cryptoTypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/simapp/params"
+	"github.com/cosmos/cosmos-sdk/std"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	authTypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	bankTypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	distrTypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	"github.com/cosmos/cosmos-sdk/x/feegrant"
+	stakingTypes "github.com/cosmos/cosmos-sdk/x/staking/types"
 )
 
 func init() {
 	conf := sdk.GetConfig()
 	SetBech32Prefixes(conf)
 	SetBip44CoinType(conf)
+
+	registerCodecs()
+}
+
+// registerCodecs registers every key, account and message type the SDK sends, so that
+// transactions can be decoded, converted to and from JSON and verified over EIP-712.
+// Only the package codecs are changed; the SDK's global codecs are shared with the rest of
+// the binary, see registerArmorCodec.
+func registerCodecs() {
+	std.RegisterInterfaces(interfaceRegistry)
+	authTypes.RegisterInterfaces(interfaceRegistry)
+	authz.RegisterInterfaces(interfaceRegistry)
+	bankTypes.RegisterInterfaces(interfaceRegistry)
+	stakingTypes.RegisterInterfaces(interfaceRegistry)
+	distrTypes.RegisterInterfaces(interfaceRegistry)
+	feegrant.RegisterInterfaces(interfaceRegistry)
+	cysicTypes.RegisterInterfaces(interfaceRegistry)
+	delegateTypes.RegisterInterfaces(interfaceRegistry)
+	govTokenTypes.RegisterInterfaces(interfaceRegistry)
+
+	interfaceRegistry.RegisterImplementations((*cryptoTypes.PubKey)(nil), &ethsecp256k1.PubKey{})
+	interfaceRegistry.RegisterImplementations((*cryptoTypes.PrivKey)(nil), &ethsecp256k1.PrivKey{})
+
+	std.RegisterLegacyAminoCodec(legacyAmino)
+	authTypes.RegisterLegacyAminoCodec(legacyAmino)
+	authz.RegisterLegacyAminoCodec(legacyAmino)
+	bankTypes.RegisterLegacyAminoCodec(legacyAmino)
+	stakingTypes.RegisterLegacyAminoCodec(legacyAmino)
+	distrTypes.RegisterLegacyAminoCodec(legacyAmino)
+	feegrant.RegisterLegacyAminoCodec(legacyAmino)
+	delegateTypes.RegisterLegacyAminoCodec(legacyAmino)
+	govTokenTypes.RegisterLegacyAminoCodec(legacyAmino)
+	legacyAmino.RegisterConcrete(&ethsecp256k1.PubKey{}, ethsecp256k1.PubKeyName, nil)
+	legacyAmino.RegisterConcrete(&ethsecp256k1.PrivKey{}, ethsecp256k1.PrivKeyName, nil)
+
+	eip712.SetEncodingConfig(params.EncodingConfig{
+		InterfaceRegistry: interfaceRegistry,
+		Codec:             cdc,
+		TxConfig:          txConfig,
+		Amino:             legacyAmino,
+	})
 }
@@ -0,0 +1,260 @@
+package gosdk
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/cysic-tech/gosdk/crypto/ethsecp256k1"
+	etherminthd "github.com/cysic-tech/gosdk/crypto/hd"
+
+	"github.com/cosmos/cosmos-sdk/codec/legacy"
+	"github.com/cosmos/cosmos-sdk/crypto"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	"github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+var (
+	armorCodecOnce sync.Once
+	armorCodecErr  error
+)
+
+// KeyringKey describes a key stored in a keyring.
+type KeyringKey struct {
+	Name    string
+	Address sdk.AccAddress
+	// Type is "local" for keys with a private key, otherwise "offline", "multi" or "ledger".
+	Type string
+}
+
+// OpenKeyring opens a Cosmos keyring with eth_secp256k1 support, compatible with the keyring
+// of the chain's CLI.
+//
+// @param backend the keyring backend: "file", "os", "test", "kwallet", "pass" or "memory"
+// @param dir the keyring directory, e.g. the CLI home; the file backend uses dir/keyring-file
+// @param passphrase the keyring passphrase of the file backend (at least 8 characters), set on first use; ignored by other backends
+// @return the keyring, or an error if it can't be opened
+func OpenKeyring(backend string, dir string, passphrase string) (keyring.Keyring, error) {
+	// the file backend prompts for the passphrase, twice when it is set
+	userInput := strings.NewReader(passphrase + "\n" + passphrase + "\n")
+
+	kr, err := keyring.New(sdk.KeyringServiceName(), backend, dir, userInput, cdc, etherminthd.EthSecp256k1Option())
+	if err != nil {
+		logger().Error("error when open keyring", "backend", backend, "dir", dir, "err", err)
+		return nil, err
+	}
+
+	return kr, nil
+}
+
+// NewSignerFromKeyring creates a Signer from the key keyName of a keyring.
+//
+// @param backend the keyring backend, see OpenKeyring
+// @param dir the keyring directory
+// @param keyName the name of the key
+// @param passphrase the keyring passphrase of the file backend
+// @return a new Signer instance, or an error if the key does not exist or has no private key
+func NewSignerFromKeyring(backend string, dir string, keyName string, passphrase string) (*Signer, error) {
+	kr, err := OpenKeyring(backend, dir, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := kr.Key(keyName)
+	if err != nil {
+		logger().Error("error when get key from keyring", "key", keyName, "err", err)
+		return nil, err
+	}
+
+	local := record.GetLocal()
+	if local == nil || local.PrivKey == nil {
+		return nil, fmt.Errorf("key %v has no private key in the keyring", keyName)
+	}
+	privKey, ok := local.PrivKey.GetCachedValue().(types.PrivKey)
+	if !ok {
+		return nil, fmt.Errorf("key %v has an unsupported private key type", keyName)
+	}
+
+	return newSignerWithPrivKey(privKey), nil
+}
+
+// ImportMnemonicToKeyring derives an eth_secp256k1 key from a mnemonic and stores it in a keyring.
+//
+// @param backend the keyring backend, see OpenKeyring
+// @param dir the keyring directory
+// @param keyName the name of the new key
+// @param passphrase the keyring passphrase of the file backend
+// @param mnemonic the mnemonic phrase
+// @param bip39Passphrase the passphrase for the mnemonic
+// @param hdPath the HD path to derive the key, BIP44HDPath if empty
+// @return the address of the key, or an error if the key exists or derivation fails
+func ImportMnemonicToKeyring(backend string, dir string, keyName string, passphrase string, mnemonic string, bip39Passphrase string, hdPath string) (sdk.AccAddress, error) {
+	kr, err := OpenKeyring(backend, dir, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	if hdPath == "" {
+		hdPath = BIP44HDPath
+	}
+	record, err := kr.NewAccount(keyName, mnemonic, bip39Passphrase, hdPath, etherminthd.EthSecp256k1)
+	if err != nil {
+		logger().Error("error when import mnemonic to keyring", "key", keyName, "err", err)
+		return nil, err
+	}
+
+	return record.GetAddress()
+}
+
+// ImportPrivateKeyToKeyring stores a raw eth_secp256k1 private key in a keyring.
+//
+// @param backend the keyring backend, see OpenKeyring
+// @param dir the keyring directory
+// @param keyName the name of the new key
+// @param passphrase the keyring passphrase of the file backend
+// @param privKey the private key bytes
+// @return the address of the key, or an error if the key exists
+func ImportPrivateKeyToKeyring(backend string, dir string, keyName string, passphrase string, privKey []byte) (sdk.AccAddress, error) {
+	if err := registerArmorCodec(); err != nil {
+		return nil, err
+	}
+	signer, err := NewSignerWithPrivateKeyAndAlgo(privKey, ethsecp256k1.KeyType)
+	if err != nil {
+		return nil, err
+	}
+
+	// the keyring imports armored keys only; the armor never leaves this function
+	const armorPassphrase = "import"
+	armor := crypto.EncryptArmorPrivKey(signer.privateKey, armorPassphrase, signer.privateKey.Type())
+	if err := ImportArmoredPrivateKey(backend, dir, keyName, passphrase, armor, armorPassphrase); err != nil {
+		return nil, err
+	}
+
+	return signer.CosmosAddr, nil
+}
+
+// ImportArmoredPrivateKey stores a private key exported by ExportArmoredPrivateKey, or by the
+// CLI's "keys export", in a keyring.
+//
+// @param backend the keyring backend, see OpenKeyring
+// @param dir the keyring directory
+// @param keyName the name of the new key
+// @param passphrase the keyring passphrase of the file backend
+// @param armor the ASCII-armored encrypted private key
+// @param armorPassphrase the passphrase the armor is encrypted with
+// @return an error if the armor can't be decrypted or the key exists
+func ImportArmoredPrivateKey(backend string, dir string, keyName string, passphrase string, armor string, armorPassphrase string) error {
+	if err := registerArmorCodec(); err != nil {
+		return err
+	}
+
+	kr, err := OpenKeyring(backend, dir, passphrase)
+	if err != nil {
+		return err
+	}
+
+	if err := kr.ImportPrivKey(keyName, armor, armorPassphrase); err != nil {
+		logger().Error("error when import private key to keyring", "key", keyName, "err", err)
+		return err
+	}
+
+	return nil
+}
+
+// ExportArmoredPrivateKey exports the private key keyName of a keyring, encrypted with
+// armorPassphrase and ASCII-armored.
+//
+// @param backend the keyring backend, see OpenKeyring
+// @param dir the keyring directory
+// @param keyName the name of the key
+// @param passphrase the keyring passphrase of the file backend
+// @param armorPassphrase the passphrase to encrypt the armor with
+// @return the armored private key, or an error if the key does not exist
+func ExportArmoredPrivateKey(backend string, dir string, keyName string, passphrase string, armorPassphrase string) (string, error) {
+	if err := registerArmorCodec(); err != nil {
+		return "", err
+	}
+
+	kr, err := OpenKeyring(backend, dir, passphrase)
+	if err != nil {
+		return "", err
+	}
+
+	armor, err := kr.ExportPrivKeyArmor(keyName, armorPassphrase)
+	if err != nil {
+		logger().Error("error when export private key from keyring", "key", keyName, "err", err)
+		return "", err
+	}
+
+	return armor, nil
+}
+
+// registerArmorCodec lets the SDK's global amino codec, with which the keyring encrypts and
+// decrypts armored private keys, encode eth_secp256k1 keys. That codec is shared with the rest
+// of the binary, e.g. a node that registers its own keys, and amino panics on a duplicate
+// registration: the keys are registered on the first use of an armor rather than in init,
+// and only those the codec can't decode yet.
+//
+// @return an error if the keys can't be registered
+func registerArmorCodec() error {
+	armorCodecOnce.Do(func() {
+		defer func() {
+			if r := recover(); r != nil {
+				armorCodecErr = fmt.Errorf("register %v keys on the SDK amino codec: %v", ethsecp256k1.KeyType, r)
+			}
+		}()
+
+		var pubKey types.PubKey
+		if !armorCodecDecodes(&ethsecp256k1.PubKey{Key: make([]byte, ethsecp256k1.PubKeySize)}, &pubKey) {
+			legacy.Cdc.RegisterConcrete(&ethsecp256k1.PubKey{}, ethsecp256k1.PubKeyName, nil)
+		}
+		var privKey types.PrivKey
+		if !armorCodecDecodes(&ethsecp256k1.PrivKey{Key: make([]byte, ethsecp256k1.PrivKeySize)}, &privKey) {
+			legacy.Cdc.RegisterConcrete(&ethsecp256k1.PrivKey{}, ethsecp256k1.PrivKeyName, nil)
+		}
+	})
+
+	return armorCodecErr
+}
+
+// armorCodecDecodes reports whether the SDK's global amino codec decodes key, encoded by the
+// package codec, into the interface target.
+func armorCodecDecodes(key interface{}, target interface{}) bool {
+	bz, err := legacyAmino.Marshal(key)
+	if err != nil {
+		return false
+	}
+
+	return legacy.Cdc.Unmarshal(bz, target) == nil
+}
+
+// ListKeyringKeys lists the keys of a keyring.
+//
+// @param backend the keyring backend, see OpenKeyring
+// @param dir the keyring directory
+// @param passphrase the keyring passphrase of the file backend
+// @return the keys, or an error if the keyring can't be read
+func ListKeyringKeys(backend string, dir string, passphrase string) ([]KeyringKey, error) {
+	kr, err := OpenKeyring(backend, dir, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := kr.List()
+	if err != nil {
+		logger().Error("error when list keyring", "err", err)
+		return nil, err
+	}
+
+	result := make([]KeyringKey, 0, len(records))
+	for _, record := range records {
+		addr, err := record.GetAddress()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, KeyringKey{Name: record.Name, Address: addr, Type: record.GetType().String()})
+	}
+
+	return result, nil
+}
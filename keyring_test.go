@@ -0,0 +1,44 @@
+package gosdk
+
+import (
+	"testing"
+
+	"github.com/cysic-tech/gosdk/crypto/ethsecp256k1"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+)
+
+func TestKeyringArmorRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	privKey, err := ethsecp256k1.GenerateKey()
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	addr, err := ImportPrivateKeyToKeyring(keyring.BackendTest, dir, "hot", "", privKey.Bytes())
+	if err != nil {
+		t.Fatalf("import private key: %v", err)
+	}
+
+	armor, err := ExportArmoredPrivateKey(keyring.BackendTest, dir, "hot", "", "armor passphrase")
+	if err != nil {
+		t.Fatalf("export armored key: %v", err)
+	}
+	if err := ImportArmoredPrivateKey(keyring.BackendTest, dir, "copy", "", armor, "wrong passphrase"); err == nil {
+		t.Fatalf("import with a wrong armor passphrase succeeded")
+	}
+	if err := ImportArmoredPrivateKey(keyring.BackendTest, dir, "copy", "", armor, "armor passphrase"); err != nil {
+		t.Fatalf("import armored key: %v", err)
+	}
+
+	signer, err := NewSignerFromKeyring(keyring.BackendTest, dir, "copy", "")
+	if err != nil {
+		t.Fatalf("signer from keyring: %v", err)
+	}
+	if !signer.Address().Equals(addr) {
+		t.Errorf("imported copy has address %v, want %v", signer.Address(), addr)
+	}
+	if _, ok := signer.PubKey().(*ethsecp256k1.PubKey); !ok {
+		t.Errorf("imported copy has a %T key, want an %v key", signer.PubKey(), ethsecp256k1.KeyType)
+	}
+}
@@ -0,0 +1,72 @@
+package gosdk
+
+import (
+	"github.com/cysic-tech/gosdk/crypto/ethsecp256k1"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+)
+
+// ScryptParams are the scrypt cost parameters a keystore file is encrypted with.
+type ScryptParams struct {
+	// N is the CPU/memory cost, a power of two.
+	N int
+	// P is the parallelization.
+	P int
+}
+
+var (
+	// StandardScryptParams are the parameters geth uses by default, about 256MB and 1s of CPU.
+	StandardScryptParams = ScryptParams{N: keystore.StandardScryptN, P: keystore.StandardScryptP}
+	// LightScryptParams are geth's --lightkdf parameters, about 4MB and 100ms of CPU.
+	LightScryptParams = ScryptParams{N: keystore.LightScryptN, P: keystore.LightScryptP}
+)
+
+// NewSignerFromKeystore creates a new Signer from an Ethereum JSON keystore (V3, scrypt or
+// pbkdf2 encrypted) as written by geth, Clef or MetaMask.
+//
+// @param keyJSON the content of the keystore file
+// @param password the password the keystore is encrypted with
+// @return a new Signer instance, or an error if the file is malformed or the password is wrong
+func NewSignerFromKeystore(keyJSON []byte, password string) (*Signer, error) {
+	key, err := keystore.DecryptKey(keyJSON, password)
+	if err != nil {
+		logger().Error("error when decrypt keystore", "err", err)
+		return nil, err
+	}
+
+	return NewSignerWithPrivateKeyAndAlgo(crypto.FromECDSA(key.PrivateKey), ethsecp256k1.KeyType)
+}
+
+// ExportKeystore encrypts the private key of the Signer into an Ethereum JSON keystore (V3)
+// that geth can import.
+//
+// @param s the Signer instance
+// @param password the password to encrypt the keystore with
+// @param scryptParams the scrypt cost, e.g. StandardScryptParams
+// @return the content of the keystore file, or ErrNotEthKey if the Signer has no Ethereum key
+func (s *Signer) ExportKeystore(password string, scryptParams ScryptParams) ([]byte, error) {
+	priv, ok := s.privateKey.(*ethsecp256k1.PrivKey)
+	if !ok {
+		return nil, ErrNotEthKey
+	}
+
+	privKey, err := crypto.ToECDSA(priv.Key)
+	if err != nil {
+		logger().Error("error when convert private key", "err", err)
+		return nil, err
+	}
+
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+	key := &keystore.Key{
+		Id:         id,
+		Address:    crypto.PubkeyToAddress(privKey.PublicKey),
+		PrivateKey: privKey,
+	}
+
+	return keystore.EncryptKey(key, password, scryptParams.N, scryptParams.P)
+}
@@ -0,0 +1,128 @@
+package gosdk
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/cysic-tech/gosdk/crypto/ethsecp256k1"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// The test vectors of the Web3 Secret Storage Definition, which geth tests against too.
+const (
+	keystoreVectorPassword = "testpassword"
+	keystoreVectorKey      = "7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d"
+	keystoreVectorAddress  = "0x008AeEda4D805471dF9b2A5B0f38A0C3bCBA786b"
+
+	keystoreVectorScrypt = `{
+		"crypto": {
+			"cipher": "aes-128-ctr",
+			"cipherparams": {"iv": "83dbcc02d8ccb40e466191a123791e0e"},
+			"ciphertext": "d172bf743a674da9cdad04534d56926ef8358534d458fffccd4e6ad2fbde479c",
+			"kdf": "scrypt",
+			"kdfparams": {"dklen": 32, "n": 262144, "r": 1, "p": 8, "salt": "ab0c7876052600dd703518d6fc3fe8984592145b591fc8fb5c6d43190334ba19"},
+			"mac": "2103ac29920d71da29f15d75b4a16dbe95cfd7ff8faea1056c33131d846e3097"
+		},
+		"id": "3198bc9c-6672-5ab3-d995-4942343ae5b6",
+		"version": 3
+	}`
+	keystoreVectorPBKDF2 = `{
+		"crypto": {
+			"cipher": "aes-128-ctr",
+			"cipherparams": {"iv": "6087dab2f9fdbbfaddc31a909735c1e6"},
+			"ciphertext": "5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46",
+			"kdf": "pbkdf2",
+			"kdfparams": {"c": 262144, "dklen": 32, "prf": "hmac-sha256", "salt": "ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"},
+			"mac": "517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"
+		},
+		"id": "3198bc9c-6672-5ab3-d995-4942343ae5b6",
+		"version": 3
+	}`
+)
+
+// signerKey returns the eth_secp256k1 private key bytes of a Signer.
+func signerKey(t *testing.T, s *Signer) []byte {
+	t.Helper()
+
+	priv, ok := s.privateKey.(*ethsecp256k1.PrivKey)
+	if !ok {
+		t.Fatalf("signer key is a %T, want an eth_secp256k1 key", s.privateKey)
+	}
+
+	return priv.Key
+}
+
+func TestNewSignerFromKeystoreVectors(t *testing.T) {
+	wantKey, err := hex.DecodeString(keystoreVectorKey)
+	if err != nil {
+		t.Fatalf("decode key: %v", err)
+	}
+
+	for name, keyJSON := range map[string]string{"scrypt": keystoreVectorScrypt, "pbkdf2": keystoreVectorPBKDF2} {
+		t.Run(name, func(t *testing.T) {
+			signer, err := NewSignerFromKeystore([]byte(keyJSON), keystoreVectorPassword)
+			if err != nil {
+				t.Fatalf("decrypt keystore: %v", err)
+			}
+			if key := signerKey(t, signer); !bytes.Equal(key, wantKey) {
+				t.Errorf("private key = %x, want %v", key, keystoreVectorKey)
+			}
+			if signer.EthAddr != common.HexToAddress(keystoreVectorAddress) {
+				t.Errorf("address = %v, want %v", signer.EthAddr, keystoreVectorAddress)
+			}
+		})
+	}
+}
+
+func TestNewSignerFromKeystoreWrongPassword(t *testing.T) {
+	if _, err := NewSignerFromKeystore([]byte(keystoreVectorPBKDF2), "wrongpassword"); !errors.Is(err, keystore.ErrDecrypt) {
+		t.Errorf("got %v, want %v", err, keystore.ErrDecrypt)
+	}
+}
+
+func TestExportKeystoreRoundTrip(t *testing.T) {
+	signer := newTestSigner(t)
+
+	keyJSON, err := signer.ExportKeystore("secret", LightScryptParams)
+	if err != nil {
+		t.Fatalf("export keystore: %v", err)
+	}
+
+	var file struct {
+		Address string `json:"address"`
+		Crypto  struct {
+			KDF       string `json:"kdf"`
+			KDFParams struct {
+				N int `json:"n"`
+				P int `json:"p"`
+			} `json:"kdfparams"`
+		} `json:"crypto"`
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(keyJSON, &file); err != nil {
+		t.Fatalf("decode keystore: %v", err)
+	}
+	if file.Version != 3 || file.Crypto.KDF != "scrypt" || file.Crypto.KDFParams.N != LightScryptParams.N || file.Crypto.KDFParams.P != LightScryptParams.P {
+		t.Errorf("keystore = %+v, want a V3 scrypt keystore with the light parameters", file)
+	}
+	if common.HexToAddress(file.Address) != signer.EthAddr {
+		t.Errorf("keystore address = %v, want %v", file.Address, signer.EthAddr)
+	}
+
+	imported, err := NewSignerFromKeystore(keyJSON, "secret")
+	if err != nil {
+		t.Fatalf("import keystore: %v", err)
+	}
+	if !bytes.Equal(signerKey(t, imported), signerKey(t, signer)) || !imported.Address().Equals(signer.Address()) {
+		t.Errorf("imported signer %v differs from the exported %v", imported.Address(), signer.Address())
+	}
+
+	if _, err := NewSignerFromKeystore(keyJSON, "not the secret"); !errors.Is(err, keystore.ErrDecrypt) {
+		t.Errorf("import with the wrong password: got %v, want %v", err, keystore.ErrDecrypt)
+	}
+}
@@ -0,0 +1,89 @@
+package gosdk
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// Logger is the structured, leveled logger of the SDK. Its methods take a message and
+// alternating key/value pairs, so a *slog.Logger can be used as is.
+//
+// Log lines carry fields such as "endpoint", "method", "txHash", "address", "latency" and "err".
+// Private keys, mnemonics, passphrases and signed bytes are never logged.
+type Logger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+// NopLogger returns a Logger that discards everything, the default Logger.
+func NopLogger() Logger {
+	return nopLogger{}
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...any) {}
+func (nopLogger) Info(string, ...any)  {}
+func (nopLogger) Warn(string, ...any)  {}
+func (nopLogger) Error(string, ...any) {}
+
+// loggerBox gives every Logger stored in defaultLogger the same concrete type.
+type loggerBox struct {
+	Logger
+}
+
+var defaultLogger atomic.Value
+
+// SetDefaultLogger sets the Logger of the package functions, such as the Signer and keyring
+// helpers, and of every Server created without WithLogger.
+//
+// @param l the Logger, or nil to restore the no-op default
+func SetDefaultLogger(l Logger) {
+	if l == nil {
+		l = NopLogger()
+	}
+	defaultLogger.Store(loggerBox{l})
+}
+
+// logger returns the default Logger.
+func logger() Logger {
+	if box, ok := defaultLogger.Load().(loggerBox); ok {
+		return box.Logger
+	}
+
+	return nopLogger{}
+}
+
+// defaultLoggerRef forwards to the default Logger at the time of each call, so that
+// SetDefaultLogger also applies to Servers created before it was called.
+type defaultLoggerRef struct{}
+
+func (defaultLoggerRef) Debug(msg string, args ...any) { logger().Debug(msg, args...) }
+func (defaultLoggerRef) Info(msg string, args ...any)  { logger().Info(msg, args...) }
+func (defaultLoggerRef) Warn(msg string, args ...any)  { logger().Warn(msg, args...) }
+func (defaultLoggerRef) Error(msg string, args ...any) { logger().Error(msg, args...) }
+
+// log returns the Logger of the Server.
+func (s *Server) log() Logger {
+	return s.logger
+}
+
+// loggingUnaryInterceptor logs every unary gRPC call at debug level with its latency.
+func loggingUnaryInterceptor(l Logger) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if err != nil {
+			l.Debug("grpc call failed", "endpoint", cc.Target(), "method", method, "latency", time.Since(start), "err", err)
+			return err
+		}
+
+		l.Debug("grpc call", "endpoint", cc.Target(), "method", method, "latency", time.Since(start))
+		return nil
+	}
+}
@@ -0,0 +1,174 @@
+package gosdk
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// logEntry is a line logged to a captureLogger.
+type logEntry struct {
+	level  string
+	msg    string
+	fields map[string]any
+}
+
+// captureLogger is a Logger keeping every line logged to it.
+type captureLogger struct {
+	mu      sync.Mutex
+	entries []logEntry
+}
+
+func (l *captureLogger) Debug(msg string, args ...any) { l.log("debug", msg, args) }
+func (l *captureLogger) Info(msg string, args ...any)  { l.log("info", msg, args) }
+func (l *captureLogger) Warn(msg string, args ...any)  { l.log("warn", msg, args) }
+func (l *captureLogger) Error(msg string, args ...any) { l.log("error", msg, args) }
+
+func (l *captureLogger) log(level string, msg string, args []any) {
+	fields := make(map[string]any, len(args)/2)
+	for i := 0; i+1 < len(args); i += 2 {
+		fields[fmt.Sprint(args[i])] = args[i+1]
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, logEntry{level: level, msg: msg, fields: fields})
+}
+
+// find returns the entries logged with msg.
+func (l *captureLogger) find(msg string) []logEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var found []logEntry
+	for _, entry := range l.entries {
+		if entry.msg == msg {
+			found = append(found, entry)
+		}
+	}
+	return found
+}
+
+// assertNoSecret fails t if a logged value contains one of secrets.
+func (l *captureLogger) assertNoSecret(t *testing.T, secrets ...string) {
+	t.Helper()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, entry := range l.entries {
+		for key, value := range entry.fields {
+			logged := strings.ToLower(fmt.Sprint(value))
+			for _, secret := range secrets {
+				if strings.Contains(logged, strings.ToLower(secret)) {
+					t.Errorf("%q logged the secret %q in %v", entry.msg, secret, key)
+				}
+			}
+		}
+	}
+}
+
+func TestLoggerFields(t *testing.T) {
+	chain := newMockChain()
+	node := chain.serve(t)
+	l := &captureLogger{}
+	s := newTestServer(t, node, WithFixedGas(), WithRetryPolicy(NoRetry()), WithLogger(l))
+
+	signer := newOfflineSigner(t, cliTxKey)
+	recipient := newTestSigner(t).Address().String()
+	chain.fund(signer.Address(), testCoins(1_000_000))
+
+	txHash, err := s.Send(signer, recipient, CYSToken, sdkmath.NewInt(10))
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if _, err := s.Send(signer, recipient, CYSToken, sdkmath.NewInt(10_000_000)); err == nil {
+		t.Fatal("send more than the balance: got no error")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := s.AwaitTxCtx(ctx, "0123456789ABCDEF0123456789ABCDEF0123456789ABCDEF0123456789ABCDEF"); err == nil {
+		t.Fatal("await unknown tx: got no error")
+	}
+
+	calls := l.find("grpc call")
+	if len(calls) == 0 {
+		t.Fatal("no grpc call logged")
+	}
+	methods := map[string]bool{}
+	for _, entry := range calls {
+		method, _ := entry.fields["method"].(string)
+		methods[method] = true
+		if _, ok := entry.fields["latency"].(time.Duration); entry.level != "debug" || entry.fields["endpoint"] != node.addr || !ok {
+			t.Errorf("grpc call %v: %v %v, want a debug line with the endpoint and latency", method, entry.level, entry.fields)
+		}
+	}
+	if !methods[broadcastMethod] || !methods[accountMethod] {
+		t.Errorf("grpc calls logged %v, want the Account and BroadcastTx calls", methods)
+	}
+	failed := l.find("grpc call failed")
+	if len(failed) == 0 || failed[0].fields["method"] != getTxMethod || failed[0].fields["err"] == nil || failed[0].level != "debug" {
+		t.Errorf("failed grpc calls = %v, want the GetTx call with its error", failed)
+	}
+
+	broadcast := l.find("tx broadcast")
+	if len(broadcast) != 1 {
+		t.Fatalf("%d tx broadcast lines, want 1", len(broadcast))
+	}
+	if fields := broadcast[0].fields; broadcast[0].level != "info" || fields["address"] != signer.Address().String() || fields["txHash"] != txHash || fields["sequence"] != uint64(0) {
+		t.Errorf("tx broadcast: %v %v, want the address, hash and sequence of %v", broadcast[0].level, fields, txHash)
+	}
+	rejected := l.find("tx rejected")
+	if len(rejected) != 1 {
+		t.Fatalf("%d tx rejected lines, want 1", len(rejected))
+	}
+	if fields := rejected[0].fields; rejected[0].level != "warn" || fields["address"] != signer.Address().String() ||
+		fields["codespace"] != sdkerrors.RootCodespace || fields["code"] != sdkerrors.ErrInsufficientFunds.ABCICode() || fields["txHash"] == "" {
+		t.Errorf("tx rejected: %v %v, want the insufficient funds rejection", rejected[0].level, fields)
+	}
+
+	l.assertNoSecret(t, cliTxKey)
+}
+
+func TestSetDefaultLogger(t *testing.T) {
+	chain := newMockChain()
+	node := chain.serve(t)
+	// created before the default Logger is set
+	s := newTestServer(t, node, WithRetryPolicy(NoRetry()))
+
+	l := &captureLogger{}
+	SetDefaultLogger(l)
+	t.Cleanup(func() { SetDefaultLogger(nil) })
+
+	const passphrase = "correct horse battery staple"
+	if _, err := NewSignerWithMnemonic(testMnemonic, passphrase, BIP44HDPath, "ed25519"); err == nil {
+		t.Fatal("unsupported algo: got no error")
+	}
+	if entries := l.find("error when new signing algo from string"); len(entries) != 1 || entries[0].level != "error" || entries[0].fields["err"] == nil {
+		t.Errorf("signing algo errors = %v, want one with the error", entries)
+	}
+
+	signer := newTestSigner(t)
+	if _, err := s.GetAccount(signer); err == nil {
+		t.Fatal("unfunded account: got no error")
+	}
+	if len(l.find("grpc call failed")) == 0 {
+		t.Error("the Server did not log to the default Logger")
+	}
+	l.assertNoSecret(t, testMnemonic, passphrase, hex.EncodeToString(signerKey(t, signer)))
+
+	SetDefaultLogger(nil)
+	before := len(l.find("grpc call failed"))
+	if _, err := s.GetAccount(signer); err == nil {
+		t.Fatal("unfunded account: got no error")
+	}
+	if after := len(l.find("grpc call failed")); after != before {
+		t.Errorf("logged %d lines after the default Logger was reset", after-before)
+	}
+}
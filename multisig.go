@@ -0,0 +1,225 @@
+package gosdk
+
+import (
+	"context"
+	"fmt"
+
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	cryptoTypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/crypto/types/multisig"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	authSigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	tmcrypto "github.com/tendermint/tendermint/crypto"
+)
+
+// multisigSignMode is the sign mode of the signatures of a multisig, which the SDK only
+// verifies over amino JSON.
+const multisigSignMode = signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON
+
+// NewMultisigPubKey creates the public key of a threshold-of-N multisig account. The order
+// of pubKeys is part of the key: every party must use the same order to get the same address.
+//
+// @param threshold the number of signatures a transaction needs
+// @param pubKeys the public keys of the parties, e.g. Signer.PubKey()
+// @return the multisig public key, or an error if the threshold is out of range
+func NewMultisigPubKey(threshold int, pubKeys []cryptoTypes.PubKey) (*kmultisig.LegacyAminoPubKey, error) {
+	if threshold <= 0 || threshold > len(pubKeys) {
+		return nil, fmt.Errorf("threshold %d out of range for %d public keys", threshold, len(pubKeys))
+	}
+	for _, pubKey := range pubKeys {
+		if pubKey == nil {
+			return nil, fmt.Errorf("public key is nil")
+		}
+	}
+
+	return kmultisig.NewLegacyAminoPubKey(threshold, pubKeys), nil
+}
+
+// MultisigAddress returns the address of the multisig account of pubKeys with threshold.
+//
+// @param threshold the number of signatures a transaction needs
+// @param pubKeys the public keys of the parties
+// @return the multisig address, or an error if the threshold is out of range
+func MultisigAddress(threshold int, pubKeys []cryptoTypes.PubKey) (sdk.AccAddress, error) {
+	pubKey, err := NewMultisigPubKey(threshold, pubKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	return multisigAddress(pubKey), nil
+}
+
+// multisigAddress returns the address of a multisig key, as pubKey.Address() does. The SDK
+// derives it from the amino encoding of the key with its own multisig codec, which can't
+// encode eth_secp256k1 keys unless the SDK's global codecs are changed; the package codec
+// produces the same encoding.
+//
+// @param pubKey the multisig public key
+// @return the address of the multisig account
+func multisigAddress(pubKey *kmultisig.LegacyAminoPubKey) sdk.AccAddress {
+	return sdk.AccAddress(tmcrypto.AddressHash(legacyAmino.MustMarshal(pubKey)))
+}
+
+// SignMultisigTxJSON produces the partial signature of signer, one of the parties of a
+// multisig, for a transaction built by BuildUnsignedTx with the multisig public key as signer.
+// It needs no network access.
+//
+// @param signer the TxSigner of the party
+// @param txJSON the transaction as JSON
+// @param chainID the chain ID of the blockchain
+// @param accNumber the account number of the multisig account
+// @return the partial signature as JSON, to pass to CombineMultisigSignatures
+func SignMultisigTxJSON(signer TxSigner, txJSON []byte, chainID string, accNumber uint64) ([]byte, error) {
+	txBuilder, sigs, err := decodeTxJSON(txJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	index := -1
+	for i, sig := range sigs {
+		if multisigPubKey, ok := sig.PubKey.(*kmultisig.LegacyAminoPubKey); ok && multisigContains(multisigPubKey, signer.PubKey()) {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("%v is not a party of a multisig signer of the tx", signer.Address().String())
+	}
+
+	bytesToSign, err := multisigSignBytes(txBuilder.GetTx(), sigs[index], chainID, accNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	sigBytes, err := signer.SignBytes(context.Background(), bytesToSign)
+	if err != nil {
+		logger().Error("error when sign msg", "err", err)
+		return nil, err
+	}
+
+	return txConfig.MarshalSignatureJSON([]signing.SignatureV2{{
+		PubKey:   signer.PubKey(),
+		Data:     &signing.SingleSignatureData{SignMode: multisigSignMode, Signature: sigBytes},
+		Sequence: sigs[index].Sequence,
+	}})
+}
+
+// CombineMultisigSignatures verifies the partial signatures of the parties of multisigPubKey
+// and sets their combination as its signature of the transaction. Once the threshold is
+// reached the result can be sent with TxJSONToBytes and BroadcastTx.
+//
+// @param txJSON the transaction as JSON
+// @param multisigPubKey the multisig public key, a signer of the transaction
+// @param chainID the chain ID of the blockchain
+// @param accNumber the account number of the multisig account
+// @param partialSigs the partial signatures produced by SignMultisigTxJSON
+// @return the transaction as JSON, or an error if a signature is invalid or too few are given
+func CombineMultisigSignatures(txJSON []byte, multisigPubKey *kmultisig.LegacyAminoPubKey, chainID string, accNumber uint64, partialSigs ...[]byte) ([]byte, error) {
+	if multisigPubKey == nil {
+		return nil, fmt.Errorf("multisig public key is nil")
+	}
+
+	txBuilder, sigs, err := decodeTxJSON(txJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	index := -1
+	for i, sig := range sigs {
+		if sig.PubKey != nil && sig.PubKey.Equals(multisigPubKey) {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("%v is not a signer of the tx", multisigAddress(multisigPubKey).String())
+	}
+
+	bytesToSign, err := multisigSignBytes(txBuilder.GetTx(), sigs[index], chainID, accNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	pubKeys := multisigPubKey.GetPubKeys()
+	multiSig := multisig.NewMultisig(len(pubKeys))
+	for _, partialSig := range partialSigs {
+		partials, err := txConfig.UnmarshalSignatureJSON(partialSig)
+		if err != nil {
+			logger().Error("error when decode partial signature", "err", err)
+			return nil, err
+		}
+
+		for _, partial := range partials {
+			data, ok := partial.Data.(*signing.SingleSignatureData)
+			if !ok || data.SignMode != multisigSignMode {
+				return nil, fmt.Errorf("partial signature must be a single %v signature", multisigSignMode)
+			}
+			if partial.Sequence != sigs[index].Sequence {
+				return nil, fmt.Errorf("partial signature has sequence %d, the tx has %d", partial.Sequence, sigs[index].Sequence)
+			}
+			if partial.PubKey == nil || !multisigContains(multisigPubKey, partial.PubKey) {
+				return nil, fmt.Errorf("partial signature is not from a party of the multisig")
+			}
+			if !partial.PubKey.VerifySignature(bytesToSign, data.Signature) {
+				return nil, fmt.Errorf("invalid partial signature of %v", sdk.AccAddress(partial.PubKey.Address()).String())
+			}
+
+			if err := multisig.AddSignatureFromPubKey(multiSig, data, partial.PubKey, pubKeys); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if count := multiSig.BitArray.NumTrueBitsBefore(len(pubKeys)); count < int(multisigPubKey.Threshold) {
+		return nil, fmt.Errorf("%d of %d required signatures given", count, multisigPubKey.Threshold)
+	}
+
+	sigs[index].Data = multiSig
+	if err := txBuilder.SetSignatures(sigs...); err != nil {
+		logger().Error("error when set signed bytes to tx", "err", err)
+		return nil, err
+	}
+
+	return txConfig.TxJSONEncoder()(txBuilder.GetTx())
+}
+
+// multisigContains reports whether pubKey is one of the parties of multisigPubKey.
+func multisigContains(multisigPubKey *kmultisig.LegacyAminoPubKey, pubKey cryptoTypes.PubKey) bool {
+	for _, key := range multisigPubKey.GetPubKeys() {
+		if key.Equals(pubKey) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// multisigSignBytes returns the amino JSON bytes the parties of the multisig signer sig sign.
+//
+// @param tx the transaction
+// @param sig the signature slot of the multisig
+// @param chainID the chain ID of the blockchain
+// @param accNumber the account number of the multisig account
+// @return the bytes to sign, or an error if the transaction has no amino JSON representation
+func multisigSignBytes(tx sdk.Tx, sig signing.SignatureV2, chainID string, accNumber uint64) ([]byte, error) {
+	multisigPubKey, ok := sig.PubKey.(*kmultisig.LegacyAminoPubKey)
+	if !ok {
+		return nil, fmt.Errorf("signer is not a multisig")
+	}
+
+	signerData := authSigning.SignerData{
+		ChainID:       chainID,
+		AccountNumber: accNumber,
+		Sequence:      sig.Sequence,
+		PubKey:        sig.PubKey,
+		Address:       multisigAddress(multisigPubKey).String(),
+	}
+
+	bytesToSign, err := txConfig.SignModeHandler().GetSignBytes(multisigSignMode, signerData, tx)
+	if err != nil {
+		logger().Error("error when get wait sign tx", "err", err)
+		return nil, err
+	}
+
+	return bytesToSign, nil
+}
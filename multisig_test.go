@@ -0,0 +1,166 @@
+package gosdk
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cysic-tech/gosdk/crypto/ethsecp256k1"
+
+	sdkmath "cosmossdk.io/math"
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptoTypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/crypto/types/multisig"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	authSigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+)
+
+// testPartyKeys returns the public keys of n new Signers.
+func testPartyKeys(t *testing.T, n int) ([]*Signer, []cryptoTypes.PubKey) {
+	t.Helper()
+
+	signers := make([]*Signer, n)
+	pubKeys := make([]cryptoTypes.PubKey, n)
+	for i := range signers {
+		signers[i] = newTestSigner(t)
+		pubKeys[i] = signers[i].PubKey()
+	}
+
+	return signers, pubKeys
+}
+
+func TestMultisigAddress(t *testing.T) {
+	cosmosKeys := []cryptoTypes.PubKey{secp256k1.GenPrivKey().PubKey(), secp256k1.GenPrivKey().PubKey(), secp256k1.GenPrivKey().PubKey()}
+	addr, err := MultisigAddress(2, cosmosKeys)
+	if err != nil {
+		t.Fatalf("multisig address: %v", err)
+	}
+	if want := sdk.AccAddress(kmultisig.NewLegacyAminoPubKey(2, cosmosKeys).Address()); !addr.Equals(want) {
+		t.Errorf("address = %v, want the SDK's %v", addr, want)
+	}
+
+	// a host binary, e.g. a node, registers its own keys on the SDK codec; amino panics on duplicates
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("registering %v on the SDK multisig codec panicked: %v", ethsecp256k1.PubKeyName, r)
+			}
+		}()
+		kmultisig.AminoCdc.RegisterConcrete(&ethsecp256k1.PubKey{}, ethsecp256k1.PubKeyName, nil)
+	}()
+
+	_, ethKeys := testPartyKeys(t, 3)
+	addr, err = MultisigAddress(2, ethKeys)
+	if err != nil {
+		t.Fatalf("multisig address: %v", err)
+	}
+	if want := sdk.AccAddress(kmultisig.NewLegacyAminoPubKey(2, ethKeys).Address()); !addr.Equals(want) {
+		t.Errorf("address = %v, want the SDK's %v", addr, want)
+	}
+}
+
+func TestMultisigCombine(t *testing.T) {
+	chain := newMockChain()
+	node := chain.serve(t)
+	s := newTestServer(t, node)
+
+	parties, pubKeys := testPartyKeys(t, 3)
+	multisigPubKey, err := NewMultisigPubKey(2, pubKeys)
+	if err != nil {
+		t.Fatalf("new multisig pub key: %v", err)
+	}
+	multisigAddr := multisigAddress(multisigPubKey)
+	chain.fund(multisigAddr, testCoins(1_000_000))
+	acc := chain.account(multisigAddr)
+
+	recipient := newTestSigner(t).Address()
+	msg, err := NewSendMsg(multisigAddr.String(), recipient.String(), CYSToken, sdkmath.NewInt(100))
+	if err != nil {
+		t.Fatalf("new send msg: %v", err)
+	}
+	unsigned, err := s.BuildUnsignedTx([]sdk.Msg{msg}, UnsignedTxParams{
+		Signers:  []TxSignerInfo{{PubKey: multisigPubKey, Sequence: acc.sequence}},
+		GasLimit: 100_000,
+		Fee:      testCoins(5_000),
+	})
+	if err != nil {
+		t.Fatalf("build unsigned tx: %v", err)
+	}
+
+	partialSigs := make([][]byte, len(parties))
+	for i, party := range parties {
+		if partialSigs[i], err = SignMultisigTxJSON(party, unsigned, testChainID, acc.number); err != nil {
+			t.Fatalf("sign by party %d: %v", i, err)
+		}
+	}
+	if _, err := SignMultisigTxJSON(newTestSigner(t), unsigned, testChainID, acc.number); err == nil {
+		t.Errorf("signing by a non-party succeeded, want an error")
+	}
+
+	// one of two required signatures
+	_, err = CombineMultisigSignatures(unsigned, multisigPubKey, testChainID, acc.number, partialSigs[1])
+	if err == nil || !strings.Contains(err.Error(), "1 of 2 required signatures") {
+		t.Errorf("combining one signature: got %v, want a threshold error", err)
+	}
+	// a partial signature over another account number
+	wrongAccount, err := SignMultisigTxJSON(parties[2], unsigned, testChainID, acc.number+1)
+	if err != nil {
+		t.Fatalf("sign by party 2: %v", err)
+	}
+	_, err = CombineMultisigSignatures(unsigned, multisigPubKey, testChainID, acc.number, partialSigs[0], wrongAccount)
+	if err == nil || !strings.Contains(err.Error(), "invalid partial signature") {
+		t.Errorf("combining a signature over other bytes: got %v, want an invalid signature error", err)
+	}
+
+	combined, err := CombineMultisigSignatures(unsigned, multisigPubKey, testChainID, acc.number, partialSigs[0], partialSigs[2])
+	if err != nil {
+		t.Fatalf("combine signatures: %v", err)
+	}
+
+	txBuilder, sigs, err := decodeTxJSON(combined)
+	if err != nil {
+		t.Fatalf("decode combined tx: %v", err)
+	}
+	multiSig, ok := sigs[0].Data.(*signing.MultiSignatureData)
+	if !ok || len(multiSig.Signatures) != 2 || !multiSig.BitArray.GetIndex(0) || multiSig.BitArray.GetIndex(1) || !multiSig.BitArray.GetIndex(2) {
+		t.Fatalf("signature data = %+v, want the signatures of parties 0 and 2", sigs[0].Data)
+	}
+	signerData := authSigning.SignerData{
+		ChainID:       testChainID,
+		AccountNumber: acc.number,
+		Sequence:      acc.sequence,
+		PubKey:        multisigPubKey,
+		Address:       multisigAddr.String(),
+	}
+	getSignBytes := func(mode signing.SignMode) ([]byte, error) {
+		return txConfig.SignModeHandler().GetSignBytes(mode, signerData, txBuilder.GetTx())
+	}
+	if err := multisigPubKey.VerifyMultisignature(getSignBytes, multiSig); err != nil {
+		t.Errorf("verify multisignature: %v", err)
+	}
+
+	// the SDK rejects a combination below the threshold
+	belowThreshold := multisig.NewMultisig(len(pubKeys))
+	if err := multisig.AddSignatureFromPubKey(belowThreshold, multiSig.Signatures[0], pubKeys[0], pubKeys); err != nil {
+		t.Fatalf("add signature: %v", err)
+	}
+	if err := multisigPubKey.VerifyMultisignature(getSignBytes, belowThreshold); err == nil {
+		t.Errorf("verifying one of two signatures succeeded, want an error")
+	}
+
+	txBytes, err := TxJSONToBytes(combined)
+	if err != nil {
+		t.Fatalf("tx json to bytes: %v", err)
+	}
+	resp, err := s.BroadcastTx(txBytes)
+	if err != nil {
+		t.Fatalf("broadcast: %v", err)
+	}
+	if resp.Code != 0 {
+		t.Fatalf("broadcast rejected: %v", resp.RawLog)
+	}
+	if got := chain.account(recipient).balance; !got.IsEqual(testCoins(100)) {
+		t.Errorf("recipient balance = %v, want %v", got, testCoins(100))
+	}
+}
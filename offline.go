@@ -0,0 +1,355 @@
+package gosdk
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	sdkmath "cosmossdk.io/math"
+	sdkClient "github.com/cosmos/cosmos-sdk/client"
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	cryptoTypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/crypto/types/multisig"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkTx "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	authSigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+)
+
+// TxSignerInfo identifies an account that signs an offline transaction.
+type TxSignerInfo struct {
+	PubKey   cryptoTypes.PubKey
+	Sequence uint64
+}
+
+// UnsignedTxParams describes a transaction built by BuildUnsignedTx.
+type UnsignedTxParams struct {
+	// Signers are the accounts that sign the transaction, in the order of the signers of its messages.
+	// A multisig public key is signed with SignMultisigTxJSON and CombineMultisigSignatures.
+	Signers []TxSignerInfo
+	// GasLimit is the gas limit, the Server's gas limit if zero.
+	GasLimit uint64
+	// Fee is the fee, the gas limit at the Server's gas price if empty.
+	Fee sdk.Coins
+	// FeePayer pays the fee, the first signer if empty.
+	FeePayer      sdk.AccAddress
+	Memo          string
+	TimeoutHeight uint64
+}
+
+// BuildUnsignedTx builds a transaction without signatures and returns it in the JSON format
+// of the Cosmos SDK CLI (tx.json). No network access is needed: the signers' sequences are
+// given explicitly and the signer infos are filled in, so that every signer signs the same bytes.
+//
+// @param msgList the messages of the transaction
+// @param params the signers, fee and other fields of the transaction
+// @return the unsigned transaction as JSON, or an error if the transaction is invalid
+func (s *Server) BuildUnsignedTx(msgList []sdk.Msg, params UnsignedTxParams) ([]byte, error) {
+	if len(msgList) == 0 {
+		return nil, fmt.Errorf("msg list is empty")
+	}
+	if len(params.Signers) == 0 {
+		return nil, fmt.Errorf("signer list is empty")
+	}
+	for _, msg := range msgList {
+		if err := msg.ValidateBasic(); err != nil {
+			s.log().Error("error when validate basic for msg", "msg", msg, "err", err)
+			return nil, err
+		}
+	}
+
+	gasLimit := params.GasLimit
+	if gasLimit == 0 {
+		gasLimit = s.gas().limit
+	}
+	fee := params.Fee
+	if fee.Empty() {
+		fee = s.feeFor(gasLimit, FeeQuote{GasPrice: sdkmath.NewInt(s.gas().price)})
+	}
+
+	txBuilder := txConfig.NewTxBuilder()
+	if err := txBuilder.SetMsgs(msgList...); err != nil {
+		s.log().Error("error when set msg", "err", err)
+		return nil, err
+	}
+	txBuilder.SetGasLimit(gasLimit)
+	txBuilder.SetFeeAmount(fee)
+	txBuilder.SetMemo(params.Memo)
+	txBuilder.SetTimeoutHeight(params.TimeoutHeight)
+	if !params.FeePayer.Empty() {
+		txBuilder.SetFeePayer(params.FeePayer)
+	}
+
+	sigs := make([]signing.SignatureV2, 0, len(params.Signers))
+	for _, info := range params.Signers {
+		if info.PubKey == nil {
+			return nil, fmt.Errorf("signer public key is nil")
+		}
+		var data signing.SignatureData = &signing.SingleSignatureData{SignMode: signMode}
+		if multisigPubKey, ok := info.PubKey.(*kmultisig.LegacyAminoPubKey); ok {
+			data = multisig.NewMultisig(len(multisigPubKey.GetPubKeys()))
+		}
+		sigs = append(sigs, signing.SignatureV2{
+			PubKey:   info.PubKey,
+			Data:     data,
+			Sequence: info.Sequence,
+		})
+	}
+	if err := txBuilder.SetSignatures(sigs...); err != nil {
+		s.log().Error("error when set signatures", "err", err)
+		return nil, err
+	}
+
+	return txConfig.TxJSONEncoder()(txBuilder.GetTx())
+}
+
+// SignTxJSON signs a transaction with signer and returns it with the signature filled in. It
+// needs no network access; signatures of other signers are kept.
+//
+// The transaction is one built by BuildUnsignedTx, or one generated by the Cosmos SDK CLI with
+// --generate-only, which carries no signer infos: the signer info of signer is then added with
+// sequence. Adding a signer info changes the bytes every signer signs, so it is only added when
+// signer is the last signer without one and nobody has signed yet; a transaction of several
+// signers is otherwise built with BuildUnsignedTx.
+//
+// @param signer the TxSigner, which must be a signer of the messages or the fee payer of the transaction
+// @param txJSON the transaction as JSON
+// @param chainID the chain ID of the blockchain
+// @param accNumber the account number of the signer
+// @param sequence the sequence of the signer, which must match the one of its signer info if present
+// @return the signed transaction as JSON, or an error if signer is not a signer of the transaction
+func SignTxJSON(signer TxSigner, txJSON []byte, chainID string, accNumber, sequence uint64) ([]byte, error) {
+	txBuilder, sigs, err := decodeTxJSON(txJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	signers := txBuilder.GetTx().GetSigners()
+	position := -1
+	for i, addr := range signers {
+		if addr.Equals(signer.Address()) {
+			position = i
+			break
+		}
+	}
+	if position < 0 {
+		return nil, fmt.Errorf("%v is not a signer of the tx", signer.Address().String())
+	}
+
+	index := -1
+	for i, sig := range sigs {
+		if sig.PubKey != nil && sig.PubKey.Equals(signer.PubKey()) {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		if sigs, err = addSignerInfo(sigs, signers, position, signer.PubKey(), sequence); err != nil {
+			return nil, err
+		}
+		index = position
+	}
+	if sigs[index].Sequence != sequence {
+		return nil, fmt.Errorf("signer %v has sequence %d in the tx, not %d", signer.Address().String(), sigs[index].Sequence, sequence)
+	}
+	if data, ok := sigs[index].Data.(*signing.SingleSignatureData); !ok || data.SignMode != signMode {
+		return nil, fmt.Errorf("signer %v does not use sign mode %v", signer.Address().String(), signMode)
+	}
+
+	// the sign bytes include the signer infos, which are set before signing
+	if err := txBuilder.SetSignatures(sigs...); err != nil {
+		logger().Error("error when set signer infos", "err", err)
+		return nil, err
+	}
+	signerData := authSigning.SignerData{
+		ChainID:       chainID,
+		AccountNumber: accNumber,
+		Sequence:      sequence,
+		PubKey:        signer.PubKey(),
+		Address:       signer.Address().String(),
+	}
+	bytesToSign, err := txConfig.SignModeHandler().GetSignBytes(signMode, signerData, txBuilder.GetTx())
+	if err != nil {
+		logger().Error("error when get wait sign tx", "err", err)
+		return nil, err
+	}
+
+	sigBytes, err := signer.SignBytes(context.Background(), bytesToSign)
+	if err != nil {
+		logger().Error("error when sign msg", "err", err)
+		return nil, err
+	}
+
+	sigs[index].Data = &signing.SingleSignatureData{SignMode: signMode, Signature: sigBytes}
+	if err := txBuilder.SetSignatures(sigs...); err != nil {
+		logger().Error("error when set signed bytes to tx", "err", err)
+		return nil, err
+	}
+
+	return txConfig.TxJSONEncoder()(txBuilder.GetTx())
+}
+
+// MergeTxSignatures combines copies of the same transaction signed by different signers into
+// one transaction carrying every signature.
+//
+// @param txJSONs the copies of the transaction as JSON
+// @return the merged transaction as JSON, or an error if the copies are not the same transaction
+func MergeTxSignatures(txJSONs ...[]byte) ([]byte, error) {
+	if len(txJSONs) == 0 {
+		return nil, fmt.Errorf("tx list is empty")
+	}
+
+	var merged *sdkTx.TxRaw
+	for i, txJSON := range txJSONs {
+		raw, err := txJSONToRaw(txJSON)
+		if err != nil {
+			return nil, err
+		}
+		if merged == nil {
+			merged = raw
+			continue
+		}
+
+		if !bytes.Equal(raw.BodyBytes, merged.BodyBytes) || !bytes.Equal(raw.AuthInfoBytes, merged.AuthInfoBytes) ||
+			len(raw.Signatures) != len(merged.Signatures) {
+			return nil, fmt.Errorf("tx %d differs from tx 0", i)
+		}
+		for j, sig := range raw.Signatures {
+			if len(sig) == 0 {
+				continue
+			}
+			if len(merged.Signatures[j]) != 0 && !bytes.Equal(merged.Signatures[j], sig) {
+				return nil, fmt.Errorf("tx %d has a conflicting signature for signer %d", i, j)
+			}
+			merged.Signatures[j] = sig
+		}
+	}
+
+	txBytes, err := merged.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	return TxBytesToJSON(txBytes)
+}
+
+// TxJSONToBytes encodes a transaction given as JSON into the bytes BroadcastTx sends.
+//
+// @param txJSON the transaction as JSON
+// @return the encoded transaction, or an error if the JSON is not a valid transaction
+func TxJSONToBytes(txJSON []byte) ([]byte, error) {
+	tx, err := txConfig.TxJSONDecoder()(txJSON)
+	if err != nil {
+		logger().Error("error when decode tx json", "err", err)
+		return nil, err
+	}
+
+	return txConfig.TxEncoder()(tx)
+}
+
+// TxBytesToJSON decodes an encoded transaction into JSON.
+//
+// @param txBytes the encoded transaction
+// @return the transaction as JSON, or an error if the bytes are not a valid transaction
+func TxBytesToJSON(txBytes []byte) ([]byte, error) {
+	tx, err := txConfig.TxDecoder()(txBytes)
+	if err != nil {
+		logger().Error("error when decode tx bytes", "err", err)
+		return nil, err
+	}
+
+	return txConfig.TxJSONEncoder()(tx)
+}
+
+// decodeTxJSON decodes a transaction given as JSON into a builder and its signatures.
+func decodeTxJSON(txJSON []byte) (sdkClient.TxBuilder, []signing.SignatureV2, error) {
+	tx, err := txConfig.TxJSONDecoder()(txJSON)
+	if err != nil {
+		logger().Error("error when decode tx json", "err", err)
+		return nil, nil, err
+	}
+	txBuilder, err := txConfig.WrapTxBuilder(tx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the SDK panics on signatures that do not pair with the signer infos
+	if p, ok := tx.(interface{ GetProtoTx() *sdkTx.Tx }); ok {
+		protoTx := p.GetProtoTx()
+		if len(protoTx.Signatures) != len(protoTx.GetAuthInfo().GetSignerInfos()) {
+			return nil, nil, fmt.Errorf("tx has %d signatures for %d signer infos", len(protoTx.Signatures), len(protoTx.GetAuthInfo().GetSignerInfos()))
+		}
+	}
+
+	sigs, err := txBuilder.GetTx().GetSignaturesV2()
+	if err != nil {
+		logger().Error("error when get signatures", "err", err)
+		return nil, nil, err
+	}
+
+	return txBuilder, sigs, nil
+}
+
+// addSignerInfo inserts the signer info of the signer at position of signers into sigs, the
+// signer infos of all other signers in order. Signed signer infos are refused, as their
+// signatures would no longer match.
+func addSignerInfo(sigs []signing.SignatureV2, signers []sdk.AccAddress, position int, pubKey cryptoTypes.PubKey, sequence uint64) ([]signing.SignatureV2, error) {
+	if len(sigs) != len(signers)-1 {
+		return nil, fmt.Errorf("tx has %d of %d signer infos, build it with BuildUnsignedTx", len(sigs), len(signers))
+	}
+	for i, sig := range sigs {
+		j := i
+		if i >= position {
+			j++
+		}
+		if !signerInfoAddress(sig.PubKey).Equals(signers[j]) {
+			return nil, fmt.Errorf("signer info %d is not of signer %v", i, signers[j].String())
+		}
+		signed := true
+		switch data := sig.Data.(type) {
+		case *signing.SingleSignatureData:
+			signed = len(data.Signature) != 0
+		case *signing.MultiSignatureData:
+			signed = len(data.Signatures) != 0
+		}
+		if signed {
+			return nil, fmt.Errorf("tx is already signed by %v", signers[j].String())
+		}
+	}
+
+	added := make([]signing.SignatureV2, 0, len(signers))
+	added = append(added, sigs[:position]...)
+	added = append(added, signing.SignatureV2{
+		PubKey:   pubKey,
+		Data:     &signing.SingleSignatureData{SignMode: signMode},
+		Sequence: sequence,
+	})
+	return append(added, sigs[position:]...), nil
+}
+
+// signerInfoAddress returns the address of the account of a signer info public key.
+func signerInfoAddress(pubKey cryptoTypes.PubKey) sdk.AccAddress {
+	if multisigPubKey, ok := pubKey.(*kmultisig.LegacyAminoPubKey); ok {
+		return multisigAddress(multisigPubKey)
+	}
+	if pubKey == nil {
+		return nil
+	}
+
+	return sdk.AccAddress(pubKey.Address())
+}
+
+// txJSONToRaw decodes a transaction given as JSON into its raw parts.
+func txJSONToRaw(txJSON []byte) (*sdkTx.TxRaw, error) {
+	txBytes, err := TxJSONToBytes(txJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := &sdkTx.TxRaw{}
+	if err := raw.Unmarshal(txBytes); err != nil {
+		return nil, err
+	}
+
+	return raw, nil
+}
@@ -0,0 +1,221 @@
+package gosdk
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+
+	sdkmath "cosmossdk.io/math"
+	cryptoTypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// cliTxJSON is a MsgSend generated by the Cosmos SDK CLI with --generate-only for the key
+// cliTxKey: its signer infos and signatures are empty.
+const cliTxJSON = `{"body":{"messages":[{"@type":"/cosmos.bank.v1beta1.MsgSend","from_address":"cysic1rfjz7r3u8t65teavh5utquj3kwvsj983qt59nr","to_address":"cysic12pg2fa9nlyeccdrjmnqp4p78dg2yk0yu43nzge","amount":[{"denom":"CYS","amount":"10"}]}],"memo":"","timeout_height":"0","extension_options":[],"non_critical_extension_options":[]},"auth_info":{"signer_infos":[],"fee":{"amount":[{"denom":"CYS","amount":"500"}],"gas_limit":"200000","payer":"","granter":""},"tip":null},"signatures":[]}`
+
+const cliTxKey = "0101010101010101010101010101010101010101010101010101010101010101"
+
+// newOfflineSigner returns a Signer with the eth_secp256k1 key given in hex.
+func newOfflineSigner(t *testing.T, key string) *Signer {
+	t.Helper()
+
+	bz, err := hex.DecodeString(key)
+	if err != nil {
+		t.Fatalf("decode key: %v", err)
+	}
+	signer, err := NewSignerWithPrivateKeyAndAlgo(bz, "eth_secp256k1")
+	if err != nil {
+		t.Fatalf("new signer: %v", err)
+	}
+
+	return signer
+}
+
+func TestOfflineMultiSignerRoundTrip(t *testing.T) {
+	chain := newMockChain()
+	node := chain.serve(t)
+	s := newTestServer(t, node)
+
+	sender, payer := newTestSigner(t), newTestSigner(t)
+	recipient := newTestSigner(t).Address()
+	chain.fund(sender.Address(), testCoins(1_000))
+	chain.fund(payer.Address(), testCoins(1_000_000))
+	chain.setSequence(sender.Address(), 2)
+	senderAcc, payerAcc := chain.account(sender.Address()), chain.account(payer.Address())
+
+	msg, err := NewSendMsg(sender.Address().String(), recipient.String(), CYSToken, sdkmath.NewInt(100))
+	if err != nil {
+		t.Fatalf("new send msg: %v", err)
+	}
+	unsigned, err := s.BuildUnsignedTx([]sdk.Msg{msg}, UnsignedTxParams{
+		Signers: []TxSignerInfo{
+			{PubKey: sender.PubKey(), Sequence: senderAcc.sequence},
+			{PubKey: payer.PubKey(), Sequence: payerAcc.sequence},
+		},
+		GasLimit: 100_000,
+		Fee:      testCoins(5_000),
+		FeePayer: payer.Address(),
+		Memo:     "offline",
+	})
+	if err != nil {
+		t.Fatalf("build unsigned tx: %v", err)
+	}
+
+	// each signer signs its own copy, as on separate machines
+	bySender, err := SignTxJSON(sender, unsigned, testChainID, senderAcc.number, senderAcc.sequence)
+	if err != nil {
+		t.Fatalf("sign by sender: %v", err)
+	}
+	byPayer, err := SignTxJSON(payer, unsigned, testChainID, payerAcc.number, payerAcc.sequence)
+	if err != nil {
+		t.Fatalf("sign by fee payer: %v", err)
+	}
+
+	merged, err := MergeTxSignatures(bySender, byPayer)
+	if err != nil {
+		t.Fatalf("merge signatures: %v", err)
+	}
+	txBytes, err := TxJSONToBytes(merged)
+	if err != nil {
+		t.Fatalf("tx json to bytes: %v", err)
+	}
+	roundTrip, err := TxBytesToJSON(txBytes)
+	if err != nil {
+		t.Fatalf("tx bytes to json: %v", err)
+	}
+	if !bytes.Equal(roundTrip, merged) {
+		t.Errorf("json round trip = %s, want %s", roundTrip, merged)
+	}
+
+	// one signature is not enough
+	partial, err := TxJSONToBytes(bySender)
+	if err != nil {
+		t.Fatalf("tx json to bytes: %v", err)
+	}
+	if resp, err := s.BroadcastTx(partial); err != nil {
+		t.Fatalf("broadcast with one signature: %v", err)
+	} else if resp.Code == 0 {
+		t.Errorf("broadcast with one signature was accepted, want a rejection")
+	}
+
+	resp, err := s.BroadcastTx(txBytes)
+	if err != nil {
+		t.Fatalf("broadcast: %v", err)
+	}
+	if resp.Code != 0 {
+		t.Fatalf("broadcast rejected: %v", resp.RawLog)
+	}
+	if got := chain.account(recipient).balance; !got.IsEqual(testCoins(100)) {
+		t.Errorf("recipient balance = %v, want %v", got, testCoins(100))
+	}
+	if got := chain.account(payer.Address()).balance; !got.IsEqual(testCoins(1_000_000 - 5_000)) {
+		t.Errorf("fee payer balance = %v, want the fee deducted", got)
+	}
+	if got := chain.account(sender.Address()).sequence; got != 3 {
+		t.Errorf("sender sequence = %d, want 3", got)
+	}
+}
+
+func TestSignCLITxJSON(t *testing.T) {
+	chain := newMockChain()
+	node := chain.serve(t)
+	s := newTestServer(t, node)
+
+	signer := newOfflineSigner(t, cliTxKey)
+	chain.fund(newTestSigner(t).Address(), testCoins(1))
+	chain.fund(signer.Address(), testCoins(1_000))
+	chain.setSequence(signer.Address(), 7)
+	acc := chain.account(signer.Address())
+
+	signed, err := SignTxJSON(signer, []byte(cliTxJSON), testChainID, acc.number, acc.sequence)
+	if err != nil {
+		t.Fatalf("sign cli tx: %v", err)
+	}
+	_, sigs, err := decodeTxJSON(signed)
+	if err != nil {
+		t.Fatalf("decode signed tx: %v", err)
+	}
+	if len(sigs) != 1 || !sigs[0].PubKey.Equals(signer.PubKey()) || sigs[0].Sequence != 7 {
+		t.Fatalf("signer infos = %+v, want one of the signer with sequence 7", sigs)
+	}
+
+	// signing again with the signer info in place gives the same transaction
+	again, err := SignTxJSON(signer, signed, testChainID, acc.number, acc.sequence)
+	if err != nil {
+		t.Fatalf("sign signed cli tx: %v", err)
+	}
+	if !bytes.Equal(again, signed) {
+		t.Errorf("signing again = %s, want %s", again, signed)
+	}
+
+	txBytes, err := TxJSONToBytes(signed)
+	if err != nil {
+		t.Fatalf("tx json to bytes: %v", err)
+	}
+	resp, err := s.BroadcastTx(txBytes)
+	if err != nil {
+		t.Fatalf("broadcast: %v", err)
+	}
+	if resp.Code != 0 {
+		t.Fatalf("broadcast rejected: %v", resp.RawLog)
+	}
+	if got := chain.account(signer.Address()).balance; !got.IsEqual(testCoins(1_000 - 500 - 10)) {
+		t.Errorf("signer balance = %v, want fee and amount deducted", got)
+	}
+}
+
+func TestSignTxJSONErrors(t *testing.T) {
+	signer := newOfflineSigner(t, cliTxKey)
+	other := newTestSigner(t)
+
+	// a tx of two signers, one of them the fee payer
+	withPayer := strings.Replace(cliTxJSON, `"payer":""`, fmt.Sprintf(`"payer":"%s"`, other.Address()), 1)
+	// withSignerInfo adds the signer info of pubKey with sequence 3 and the empty signature the CLI gives it
+	withSignerInfo := func(txJSON string, pubKey cryptoTypes.PubKey) string {
+		pubKeyJSON, err := cdc.MarshalInterfaceJSON(pubKey)
+		if err != nil {
+			t.Fatalf("marshal pub key: %v", err)
+		}
+		info := fmt.Sprintf(`"signer_infos":[{"public_key":%s,"mode_info":{"single":{"mode":"SIGN_MODE_DIRECT"}},"sequence":"3"}]`, pubKeyJSON)
+		txJSON = strings.Replace(txJSON, `"signer_infos":[]`, info, 1)
+		return strings.Replace(txJSON, `"signatures":[]`, `"signatures":[""]`, 1)
+	}
+
+	cases := []struct {
+		name   string
+		signer TxSigner
+		txJSON string
+		want   string
+	}{
+		{"not a signer", other, cliTxJSON, "is not a signer of the tx"},
+		{"other signer infos missing", signer, withPayer, "signer infos, build it with BuildUnsignedTx"},
+		{"sequence differs from signer info", signer, withSignerInfo(cliTxJSON, signer.PubKey()), "has sequence 3 in the tx, not 0"},
+		{"signatures without signer infos", signer, strings.Replace(cliTxJSON, `"signatures":[]`, `"signatures":[""]`, 1), "signatures for 0 signer infos"},
+		{"signer info of another account", signer, withSignerInfo(withPayer, newTestSigner(t).PubKey()), "is not of signer"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := SignTxJSON(c.signer, []byte(c.txJSON), testChainID, 0, 0)
+			if err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Errorf("got %v, want an error containing %q", err, c.want)
+			}
+		})
+	}
+
+	// the fee payer adds its signer info after the one of the sender, which has not signed yet
+	payerFirst := withSignerInfo(withPayer, signer.PubKey())
+	signed, err := SignTxJSON(other, []byte(payerFirst), testChainID, 0, 0)
+	if err != nil {
+		t.Fatalf("sign as fee payer: %v", err)
+	}
+	_, sigs, err := decodeTxJSON(signed)
+	if err != nil {
+		t.Fatalf("decode signed tx: %v", err)
+	}
+	if len(sigs) != 2 || !sigs[0].PubKey.Equals(signer.PubKey()) || !sigs[1].PubKey.Equals(other.PubKey()) {
+		t.Errorf("signer infos = %+v, want the sender then the fee payer", sigs)
+	}
+}
@@ -0,0 +1,468 @@
+package gosdk
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+)
+
+// Option configures a Server created by NewServer.
+type Option func(*serverOptions) error
+
+type serverOptions struct {
+	gasCoin       string
+	gasPrice      int64
+	gasLimit      uint64
+	autoGas       bool
+	gasAdjustment float64
+	gasFloor      uint64
+	gasCeiling    uint64
+	feeStrategy   FeeStrategy
+	signMode      TxSignMode
+
+	transportCreds credentials.TransportCredentials
+	perRPCCreds    []credentials.PerRPCCredentials
+	keepalive      *keepalive.ClientParameters
+	maxMsgSize     int
+	userAgent      string
+	dialOpts       []grpc.DialOption
+
+	endpoints           []string
+	healthCheckInterval time.Duration
+	maxHeightLag        int64
+
+	retryPolicy    RetryPolicy
+	localSequences bool
+
+	logger         Logger
+	tracerProvider trace.TracerProvider
+	metrics        *Metrics
+}
+
+func defaultServerOptions() *serverOptions {
+	return &serverOptions{
+		gasCoin:             CYSToken,
+		gasLimit:            gasLimit,
+		autoGas:             true,
+		gasAdjustment:       defaultGasAdjustment,
+		signMode:            SignModeDirect,
+		healthCheckInterval: defaultHealthCheckInterval,
+		maxHeightLag:        defaultMaxHeightLag,
+		retryPolicy:         DefaultRetryPolicy(),
+		logger:              defaultLoggerRef{},
+		tracerProvider:      otel.GetTracerProvider(),
+	}
+}
+
+// grpcDialOptions assembles the dial options every connection of the Server is created with.
+//
+// @param chainID the chain ID the spans of the calls are tagged with
+func (o *serverOptions) grpcDialOptions(chainID string) []grpc.DialOption {
+	creds := o.transportCreds
+	if creds == nil {
+		creds = insecure.NewCredentials()
+	}
+
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		// gogoproto messages with custom types, such as sdk.Int fields, need the SDK's codec
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(cdc.GRPCCodec())),
+		grpc.WithChainUnaryInterceptor(
+			telemetryUnaryInterceptor(o.tracerProvider, o.metrics, chainID),
+			loggingUnaryInterceptor(o.logger),
+		),
+	}
+	for _, perRPC := range o.perRPCCreds {
+		opts = append(opts, grpc.WithPerRPCCredentials(perRPC))
+	}
+	if o.keepalive != nil {
+		opts = append(opts, grpc.WithKeepaliveParams(*o.keepalive))
+	}
+	if o.maxMsgSize > 0 {
+		opts = append(opts, grpc.WithDefaultCallOptions(
+			grpc.MaxCallRecvMsgSize(o.maxMsgSize),
+			grpc.MaxCallSendMsgSize(o.maxMsgSize),
+		))
+	}
+	if o.userAgent != "" {
+		opts = append(opts, grpc.WithUserAgent(o.userAgent))
+	}
+
+	return append(opts, o.dialOpts...)
+}
+
+// WithGas sets the coin and price used to pay transaction fees.
+//
+// @param gasCoin the coin to use for gas fees
+// @param gasPrice the price of gas
+func WithGas(gasCoin string, gasPrice int64) Option {
+	return func(o *serverOptions) error {
+		if gasCoin == "" {
+			return fmt.Errorf("gas coin can't be empty")
+		}
+		if gasPrice < 0 {
+			return fmt.Errorf("gas price can't be negative, got %d", gasPrice)
+		}
+
+		o.gasCoin = gasCoin
+		o.gasPrice = gasPrice
+		return nil
+	}
+}
+
+// WithGasLimit overrides the default gas limit of 15,000,000. With automatic gas estimation
+// it is the ceiling for estimates unless WithGasBounds sets one; with WithFixedGas every
+// transaction uses it.
+//
+// @param limit the gas limit to set on every transaction
+func WithGasLimit(limit uint64) Option {
+	return func(o *serverOptions) error {
+		if limit == 0 {
+			return fmt.Errorf("gas limit can't be zero")
+		}
+
+		o.gasLimit = limit
+		return nil
+	}
+}
+
+// WithGasAdjustment sets the multiplier applied to the simulated gas usage, 1.3 by default.
+//
+// @param adjustment the multiplier, at least 1
+func WithGasAdjustment(adjustment float64) Option {
+	return func(o *serverOptions) error {
+		if adjustment < 1 {
+			return fmt.Errorf("gas adjustment must be at least 1, got %v", adjustment)
+		}
+
+		o.gasAdjustment = adjustment
+		return nil
+	}
+}
+
+// WithGasBounds bounds estimated gas limits: estimates below floor are raised to it and
+// estimates above ceiling are rejected. A zero ceiling means the gas limit.
+//
+// @param floor the minimum gas limit
+// @param ceiling the maximum gas limit, or zero
+func WithGasBounds(floor, ceiling uint64) Option {
+	return func(o *serverOptions) error {
+		if ceiling != 0 && floor > ceiling {
+			return fmt.Errorf("gas floor %d is above gas ceiling %d", floor, ceiling)
+		}
+
+		o.gasFloor = floor
+		o.gasCeiling = ceiling
+		return nil
+	}
+}
+
+// WithFixedGas disables gas estimation: every transaction is signed with the gas limit.
+func WithFixedGas() Option {
+	return func(o *serverOptions) error {
+		o.autoGas = false
+		return nil
+	}
+}
+
+// WithFeeStrategy sets how the gas price of transactions is chosen, for example
+// BaseFeePlusTip for dynamic fee transactions. The default is FixedFee at the gas price.
+//
+// @param strategy the fee strategy
+func WithFeeStrategy(strategy FeeStrategy) Option {
+	return func(o *serverOptions) error {
+		if strategy == nil {
+			return fmt.Errorf("fee strategy is nil")
+		}
+
+		o.feeStrategy = strategy
+		return nil
+	}
+}
+
+// WithSignMode sets the sign mode of transactions, SignModeDirect by default. With
+// SignModeEIP712 transactions are signed over EIP-712 typed data like MetaMask signs them.
+//
+// @param mode the sign mode
+func WithSignMode(mode TxSignMode) Option {
+	return func(o *serverOptions) error {
+		switch mode {
+		case SignModeDefault:
+			o.signMode = SignModeDirect
+		case SignModeDirect, SignModeEIP712:
+			o.signMode = mode
+		default:
+			return fmt.Errorf("unknown sign mode %v", mode)
+		}
+
+		return nil
+	}
+}
+
+// WithInsecure dials without transport security. This is the default.
+func WithInsecure() Option {
+	return func(o *serverOptions) error {
+		o.transportCreds = insecure.NewCredentials()
+		return nil
+	}
+}
+
+// WithSystemTLS dials over TLS, verifying the server against the system root CAs.
+func WithSystemTLS() Option {
+	return WithTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12})
+}
+
+// WithTLSConfig dials over TLS using the given configuration.
+//
+// @param cfg the TLS configuration, e.g. with custom RootCAs or client Certificates
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(o *serverOptions) error {
+		if cfg == nil {
+			return fmt.Errorf("tls config can't be nil")
+		}
+
+		o.transportCreds = credentials.NewTLS(cfg)
+		return nil
+	}
+}
+
+// WithCACertFile dials over TLS, verifying the server against the PEM encoded CA in caFile.
+//
+// @param caFile path to the PEM encoded CA certificate
+func WithCACertFile(caFile string) Option {
+	return WithMutualTLS(caFile, "", "")
+}
+
+// WithMutualTLS dials over TLS presenting a client certificate.
+// An empty caFile falls back to the system root CAs.
+//
+// @param caFile path to the PEM encoded CA certificate, may be empty
+// @param certFile path to the PEM encoded client certificate, may be empty
+// @param keyFile path to the PEM encoded client private key, may be empty
+func WithMutualTLS(caFile, certFile, keyFile string) Option {
+	return func(o *serverOptions) error {
+		cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+
+		if caFile != "" {
+			pem, err := os.ReadFile(caFile)
+			if err != nil {
+				return fmt.Errorf("read ca file %v: %w", caFile, err)
+			}
+
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				return fmt.Errorf("no certificate found in ca file %v", caFile)
+			}
+			cfg.RootCAs = pool
+		}
+
+		if certFile != "" || keyFile != "" {
+			cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+			if err != nil {
+				return fmt.Errorf("load client key pair: %w", err)
+			}
+			cfg.Certificates = []tls.Certificate{cert}
+		}
+
+		o.transportCreds = credentials.NewTLS(cfg)
+		return nil
+	}
+}
+
+// WithPerRPCCredentials attaches creds (e.g. OAuth tokens) to every call.
+//
+// @param creds the per-RPC credentials
+func WithPerRPCCredentials(creds credentials.PerRPCCredentials) Option {
+	return func(o *serverOptions) error {
+		if creds == nil {
+			return fmt.Errorf("per rpc credentials can't be nil")
+		}
+
+		o.perRPCCreds = append(o.perRPCCreds, creds)
+		return nil
+	}
+}
+
+// WithHeaders attaches static metadata, such as API keys, to every call.
+//
+// @param headers the metadata keys and values
+func WithHeaders(headers map[string]string) Option {
+	md := make(map[string]string, len(headers))
+	for k, v := range headers {
+		md[k] = v
+	}
+
+	return WithPerRPCCredentials(headerCredentials(md))
+}
+
+// WithKeepalive sets the client keepalive parameters.
+//
+// @param params the keepalive parameters
+func WithKeepalive(params keepalive.ClientParameters) Option {
+	return func(o *serverOptions) error {
+		o.keepalive = &params
+		return nil
+	}
+}
+
+// WithMaxMsgSize sets the maximum size of messages sent and received.
+//
+// @param bytes the maximum message size in bytes
+func WithMaxMsgSize(bytes int) Option {
+	return func(o *serverOptions) error {
+		if bytes <= 0 {
+			return fmt.Errorf("max msg size must be positive, got %d", bytes)
+		}
+
+		o.maxMsgSize = bytes
+		return nil
+	}
+}
+
+// WithUserAgent sets the user agent sent on every connection.
+//
+// @param userAgent the user agent string
+func WithUserAgent(userAgent string) Option {
+	return func(o *serverOptions) error {
+		o.userAgent = userAgent
+		return nil
+	}
+}
+
+// WithDialOptions appends arbitrary gRPC dial options, applied after all other options.
+//
+// @param opts the dial options
+func WithDialOptions(opts ...grpc.DialOption) Option {
+	return func(o *serverOptions) error {
+		o.dialOpts = append(o.dialOpts, opts...)
+		return nil
+	}
+}
+
+// WithEndpoints adds fallback gRPC endpoints. Queries are routed to the healthiest endpoint
+// and fail over on Unavailable/DeadlineExceeded; broadcasts of one account stay on one endpoint.
+//
+// @param endpoints the additional endpoints, in order of preference
+func WithEndpoints(endpoints ...string) Option {
+	return func(o *serverOptions) error {
+		for _, endpoint := range endpoints {
+			if endpoint == "" {
+				return fmt.Errorf("endpoint can't be empty")
+			}
+		}
+
+		o.endpoints = append(o.endpoints, endpoints...)
+		return nil
+	}
+}
+
+// WithHealthCheck tunes endpoint health checking, which only runs with more than one endpoint.
+//
+// @param interval the time between two health checks, zero disables them
+// @param maxHeightLag how many blocks an endpoint may trail the best one and still serve queries
+func WithHealthCheck(interval time.Duration, maxHeightLag int64) Option {
+	return func(o *serverOptions) error {
+		if interval < 0 || maxHeightLag < 0 {
+			return fmt.Errorf("health check interval and max height lag can't be negative")
+		}
+
+		o.healthCheckInterval = interval
+		o.maxHeightLag = maxHeightLag
+		return nil
+	}
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy for queries and broadcasts; use NoRetry to disable retries.
+//
+// @param policy the retry policy
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(o *serverOptions) error {
+		if policy.Jitter < 0 || policy.Jitter > 1 {
+			return fmt.Errorf("retry jitter must be between 0 and 1, got %v", policy.Jitter)
+		}
+		if policy.InitialBackoff < 0 || policy.MaxBackoff < 0 {
+			return fmt.Errorf("retry backoff can't be negative")
+		}
+
+		o.retryPolicy = policy
+		return nil
+	}
+}
+
+// WithLocalSequences allocates account sequences locally with a SequenceManager instead of
+// querying the chain before every transaction, so one account can send several transactions
+// per block. Only one process should send from an account with this option.
+func WithLocalSequences() Option {
+	return func(o *serverOptions) error {
+		o.localSequences = true
+		return nil
+	}
+}
+
+// WithLogger sets the Logger of the Server, e.g. a *slog.Logger. Without it the Server logs
+// to the Logger set with SetDefaultLogger, which discards everything by default.
+//
+// @param logger the Logger
+func WithLogger(logger Logger) Option {
+	return func(o *serverOptions) error {
+		if logger == nil {
+			return fmt.Errorf("logger can't be nil")
+		}
+
+		o.logger = logger
+		return nil
+	}
+}
+
+// WithTracerProvider sets the OpenTelemetry TracerProvider of the Server. Every gRPC call is
+// traced as a client span, and every transaction as a BroadcastMsgs span with a child span per
+// stage: account_lookup, fee_quote, gas_estimate, sign and broadcast. Without it the Server
+// uses the global TracerProvider, which records nothing unless otel.SetTracerProvider is called.
+//
+// @param tp the TracerProvider
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(o *serverOptions) error {
+		if tp == nil {
+			return fmt.Errorf("tracer provider can't be nil")
+		}
+
+		o.tracerProvider = tp
+		return nil
+	}
+}
+
+// WithMetrics records the gRPC calls and transactions of the Server in m, see Metrics.
+// Without it no metrics are recorded.
+//
+// @param m the Metrics, created with NewMetrics
+func WithMetrics(m *Metrics) Option {
+	return func(o *serverOptions) error {
+		if m == nil {
+			return fmt.Errorf("metrics can't be nil")
+		}
+
+		o.metrics = m
+		return nil
+	}
+}
+
+// headerCredentials sends static metadata with every call, over both secure and insecure transports.
+type headerCredentials map[string]string
+
+func (h headerCredentials) GetRequestMetadata(_ context.Context, _ ...string) (map[string]string, error) {
+	return h, nil
+}
+
+func (h headerCredentials) RequireTransportSecurity() bool {
+	return false
+}
@@ -0,0 +1,387 @@
+package gosdk
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultHealthCheckInterval is how often endpoints are probed when more than one is configured.
+	defaultHealthCheckInterval = BlockTime
+	// defaultMaxHeightLag is how many blocks a node may trail the best known height and still serve queries.
+	defaultMaxHeightLag = int64(5)
+	// healthCheckTimeout bounds a single probe of one endpoint.
+	healthCheckTimeout = 5 * time.Second
+)
+
+// EndpointStatus is a snapshot of the health of one gRPC endpoint.
+type EndpointStatus struct {
+	Endpoint  string
+	Height    int64
+	Syncing   bool
+	Healthy   bool
+	LastError error
+	CheckedAt time.Time
+}
+
+type endpointNode struct {
+	endpoint string
+	conn     *grpc.ClientConn
+
+	height    int64
+	syncing   bool
+	healthy   bool
+	lastErr   error
+	checkedAt time.Time
+}
+
+// connPool holds one connection per endpoint and routes calls to the healthiest one.
+//
+// It implements grpc.ClientConnInterface, so module query clients can be created on it directly:
+// unary calls fail over to the next node on Unavailable or DeadlineExceeded.
+type connPool struct {
+	mu    sync.RWMutex
+	nodes []*endpointNode
+	pins  map[string]*endpointNode
+
+	dialOpts     []grpc.DialOption
+	maxHeightLag int64
+	logger       Logger
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+var _ grpc.ClientConnInterface = (*connPool)(nil)
+
+// newConnPool dials every endpoint. Dialing is non-blocking, so unreachable nodes only
+// show up as unhealthy after the first failed call or health check.
+//
+// @param ctx the context controlling the dials
+// @param endpoints the gRPC endpoints, the first one being preferred on ties
+// @param dialOpts the dial options applied to every endpoint
+// @param maxHeightLag the number of blocks a node may trail the best one
+// @param logger the Logger health checks and failovers are logged to
+// @return the pool, or an error if any endpoint cannot be dialed
+func newConnPool(ctx context.Context, endpoints []string, dialOpts []grpc.DialOption, maxHeightLag int64, logger Logger) (*connPool, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("at least one endpoint is required")
+	}
+
+	p := &connPool{
+		pins:         make(map[string]*endpointNode),
+		dialOpts:     dialOpts,
+		maxHeightLag: maxHeightLag,
+		logger:       logger,
+		stop:         make(chan struct{}),
+	}
+	for _, endpoint := range endpoints {
+		conn, err := grpc.DialContext(ctx, endpoint, dialOpts...)
+		if err != nil {
+			p.closeConns()
+			return nil, fmt.Errorf("dial %v: %w", endpoint, err)
+		}
+
+		p.nodes = append(p.nodes, &endpointNode{endpoint: endpoint, conn: conn, healthy: true})
+	}
+
+	return p, nil
+}
+
+// startHealthCheck probes all endpoints immediately and then every interval until close.
+//
+// @param interval the time between two rounds of probes
+func (p *connPool) startHealthCheck(interval time.Duration) {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			p.checkAll()
+
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// checkAll probes every endpoint concurrently for its latest height and sync state.
+func (p *connPool) checkAll() {
+	p.mu.RLock()
+	nodes := append([]*endpointNode(nil), p.nodes...)
+	p.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, node := range nodes {
+		wg.Add(1)
+		go func(node *endpointNode) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+			defer cancel()
+
+			height, syncing, err := probeEndpoint(ctx, p.connOf(node))
+
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			node.checkedAt = time.Now()
+			node.lastErr = err
+			if err != nil {
+				p.logger.Warn("health check failed", "endpoint", node.endpoint, "err", err)
+				node.healthy = false
+				return
+			}
+			node.height = height
+			node.syncing = syncing
+			node.healthy = true
+		}(node)
+	}
+	wg.Wait()
+}
+
+// probeEndpoint queries the tendermint service of one node.
+//
+// @param ctx the context bounding the probe
+// @param conn the connection to the node
+// @return the latest block height, whether the node is catching up, or an error
+func probeEndpoint(ctx context.Context, conn *grpc.ClientConn) (int64, bool, error) {
+	client := tmservice.NewServiceClient(conn)
+
+	syncResp, err := client.GetSyncing(ctx, &tmservice.GetSyncingRequest{})
+	if err != nil {
+		return 0, false, err
+	}
+
+	blockResp, err := client.GetLatestBlock(ctx, &tmservice.GetLatestBlockRequest{})
+	if err != nil {
+		return 0, false, err
+	}
+
+	return blockResp.GetBlock().GetHeader().Height, syncResp.Syncing, nil
+}
+
+// ranked returns the nodes ordered from most to least preferred: healthy, not syncing nodes
+// within maxHeightLag of the best height first, then by height, then by configuration order.
+func (p *connPool) ranked() []*endpointNode {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.rankedLocked()
+}
+
+func (p *connPool) rankedLocked() []*endpointNode {
+	var best int64
+	for _, node := range p.nodes {
+		if node.healthy && node.height > best {
+			best = node.height
+		}
+	}
+
+	usable := func(node *endpointNode) bool {
+		return node.healthy && !node.syncing && best-node.height <= p.maxHeightLag
+	}
+
+	result := append([]*endpointNode(nil), p.nodes...)
+	sort.SliceStable(result, func(i, j int) bool {
+		if ui, uj := usable(result[i]), usable(result[j]); ui != uj {
+			return ui
+		}
+		if result[i].healthy != result[j].healthy {
+			return result[i].healthy
+		}
+		return result[i].height > result[j].height
+	})
+
+	return result
+}
+
+// best returns the connection of the most preferred node.
+func (p *connPool) best() *grpc.ClientConn {
+	return p.connOf(p.ranked()[0])
+}
+
+// connOf returns the current connection of node, which reconnect may replace.
+func (p *connPool) connOf(node *endpointNode) *grpc.ClientConn {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return node.conn
+}
+
+// primary returns the connection of the first configured endpoint.
+func (p *connPool) primary() *grpc.ClientConn {
+	return p.connOf(p.nodes[0])
+}
+
+// pinned returns a connection that keeps routing to the same node for key, as long as
+// that node stays usable. Broadcasts of one account are pinned so that consecutive
+// sequences land in the same mempool.
+//
+// @param key the pin key, usually the signer's address
+// @return a connection bound to a single node
+func (p *connPool) pinned(key string) grpc.ClientConnInterface {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if node, ok := p.pins[key]; ok && node.healthy && !node.syncing {
+		return &pinnedConn{pool: p, key: key, node: node}
+	}
+
+	node := p.rankedLocked()[0]
+	p.pins[key] = node
+	return &pinnedConn{pool: p, key: key, node: node}
+}
+
+// markFailed flags a node as unhealthy after a transport failure, until the next health check.
+func (p *connPool) markFailed(node *endpointNode, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	node.healthy = false
+	node.lastErr = err
+}
+
+// Invoke performs a unary call on the best node, failing over to the next one on
+// Unavailable or DeadlineExceeded as long as ctx is not done.
+func (p *connPool) Invoke(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+	var err error
+	for _, node := range p.ranked() {
+		err = p.connOf(node).Invoke(ctx, method, args, reply, opts...)
+		if !isFailoverError(err) || ctx.Err() != nil {
+			return err
+		}
+
+		p.logger.Warn("endpoint failed, trying next", "endpoint", node.endpoint, "method", method, "err", err)
+		p.markFailed(node, err)
+	}
+
+	return err
+}
+
+// NewStream opens a stream on the best node; streams do not fail over.
+func (p *connPool) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return p.best().NewStream(ctx, desc, method, opts...)
+}
+
+// reconnect re-dials every node whose connection was shut down.
+//
+// @param ctx the context controlling the dials
+// @return an error if a node could not be re-dialed
+func (p *connPool) reconnect(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, node := range p.nodes {
+		if node.conn != nil && node.conn.GetState() != connectivity.Shutdown {
+			continue
+		}
+
+		conn, err := grpc.DialContext(ctx, node.endpoint, p.dialOpts...)
+		if err != nil {
+			return fmt.Errorf("dial %v: %w", node.endpoint, err)
+		}
+		node.conn = conn
+	}
+
+	return nil
+}
+
+// statuses returns a snapshot of every node in configuration order.
+func (p *connPool) statuses() []EndpointStatus {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	result := make([]EndpointStatus, 0, len(p.nodes))
+	for _, node := range p.nodes {
+		result = append(result, EndpointStatus{
+			Endpoint:  node.endpoint,
+			Height:    node.height,
+			Syncing:   node.syncing,
+			Healthy:   node.healthy,
+			LastError: node.lastErr,
+			CheckedAt: node.checkedAt,
+		})
+	}
+
+	return result
+}
+
+// close stops health checking and closes every connection.
+func (p *connPool) close() error {
+	p.stopOnce.Do(func() { close(p.stop) })
+	p.wg.Wait()
+
+	return p.closeConns()
+}
+
+func (p *connPool) closeConns() error {
+	var firstErr error
+	for _, node := range p.nodes {
+		if node.conn == nil {
+			continue
+		}
+		if err := node.conn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+// pinnedConn routes every call to one node and moves the pin if that node fails.
+type pinnedConn struct {
+	pool *connPool
+	key  string
+	node *endpointNode
+}
+
+var _ grpc.ClientConnInterface = (*pinnedConn)(nil)
+
+// Invoke performs a unary call on the pinned node. On a transport failure the node is
+// marked unhealthy and the pin is dropped, so the next pinned() call picks a new node;
+// the failed call itself is not retried elsewhere.
+func (c *pinnedConn) Invoke(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+	err := c.pool.connOf(c.node).Invoke(ctx, method, args, reply, opts...)
+	if isFailoverError(err) && ctx.Err() == nil {
+		c.pool.markFailed(c.node, err)
+
+		c.pool.mu.Lock()
+		if c.pool.pins[c.key] == c.node {
+			delete(c.pool.pins, c.key)
+		}
+		c.pool.mu.Unlock()
+	}
+
+	return err
+}
+
+// NewStream opens a stream on the pinned node.
+func (c *pinnedConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return c.pool.connOf(c.node).NewStream(ctx, desc, method, opts...)
+}
+
+// isFailoverError reports whether err indicates the node itself is unreachable or too slow.
+func isFailoverError(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,152 @@
+package gosdk
+
+import (
+	"testing"
+
+	sdkmath "cosmossdk.io/math"
+)
+
+const allBalancesMethod = "/cosmos.bank.v1beta1.Query/AllBalances"
+
+// newTestPool starts three nodes of one chain and a Server on all of them, in that order.
+// Health checks only run when the test calls checkAll.
+func newTestPool(t *testing.T, opts ...Option) (*mockChain, []*mockNode, *Server) {
+	t.Helper()
+
+	chain := newMockChain()
+	nodes := []*mockNode{chain.serve(t), chain.serve(t), chain.serve(t)}
+	opts = append([]Option{WithEndpoints(nodes[1].addr, nodes[2].addr), WithHealthCheck(0, 5)}, opts...)
+
+	return chain, nodes, newTestServer(t, nodes[0], opts...)
+}
+
+func TestPoolFailover(t *testing.T) {
+	chain, nodes, s := newTestPool(t, WithRetryPolicy(NoRetry()))
+	signer := newTestSigner(t)
+	chain.fund(signer.Address(), testCoins(1_000))
+
+	nodes[0].srv.Stop()
+
+	coins, err := s.GetBalanceList(signer.Address().String())
+	if err != nil {
+		t.Fatalf("query with the first endpoint down: %v", err)
+	}
+	if !coins.IsEqual(testCoins(1_000)) {
+		t.Errorf("balances = %v, want %v", coins, testCoins(1_000))
+	}
+	if nodes[1].callCount(allBalancesMethod) != 1 {
+		t.Errorf("query was not failed over to the second endpoint")
+	}
+
+	statuses := s.EndpointStatuses()
+	if len(statuses) != 3 {
+		t.Fatalf("got %d endpoint statuses, want 3", len(statuses))
+	}
+	for i, st := range statuses {
+		if st.Endpoint != nodes[i].addr {
+			t.Errorf("status %d is of %v, want %v", i, st.Endpoint, nodes[i].addr)
+		}
+	}
+	if statuses[0].Healthy || statuses[0].LastError == nil {
+		t.Errorf("stopped endpoint: %+v, want unhealthy with an error", statuses[0])
+	}
+	if !statuses[1].Healthy || !statuses[2].Healthy {
+		t.Errorf("running endpoints: %+v, want healthy", statuses[1:])
+	}
+}
+
+func TestPoolHealthCheck(t *testing.T) {
+	chain, nodes, s := newTestPool(t)
+	signer := newTestSigner(t)
+	chain.fund(signer.Address(), testCoins(1_000))
+
+	nodes[0].setLag(10)
+	nodes[2].srv.Stop()
+	s.pool.checkAll()
+
+	height := chain.latestHeight()
+	statuses := s.EndpointStatuses()
+	if st := statuses[0]; !st.Healthy || st.Height != height-10 || st.CheckedAt.IsZero() {
+		t.Errorf("lagging endpoint: %+v, want healthy at height %d", st, height-10)
+	}
+	if st := statuses[1]; !st.Healthy || st.Height != height || st.LastError != nil {
+		t.Errorf("up to date endpoint: %+v, want healthy at height %d", st, height)
+	}
+	if st := statuses[2]; st.Healthy || st.LastError == nil {
+		t.Errorf("stopped endpoint: %+v, want unhealthy with an error", st)
+	}
+
+	// the lagging node trails by more than maxHeightLag, queries skip it
+	for i := 0; i < 3; i++ {
+		if _, err := s.GetBalanceList(signer.Address().String()); err != nil {
+			t.Fatalf("query: %v", err)
+		}
+	}
+	if n := nodes[0].callCount(allBalancesMethod); n != 0 {
+		t.Errorf("lagging endpoint served %d queries, want 0", n)
+	}
+	if n := nodes[1].callCount(allBalancesMethod); n != 3 {
+		t.Errorf("up to date endpoint served %d queries, want 3", n)
+	}
+
+	// a node that caught up serves again, preferred in configuration order
+	nodes[0].setLag(0)
+	s.pool.checkAll()
+	if _, err := s.GetBalanceList(signer.Address().String()); err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if n := nodes[0].callCount(allBalancesMethod); n != 1 {
+		t.Errorf("caught up endpoint served %d queries, want 1", n)
+	}
+}
+
+func TestPoolPinsAccount(t *testing.T) {
+	chain, nodes, s := newTestPool(t)
+	signer := newTestSigner(t)
+	chain.fund(signer.Address(), testCoins(1_000))
+	recipient := newTestSigner(t).Address().String()
+	s.pool.checkAll()
+
+	send := func() {
+		t.Helper()
+		if _, err := s.Send(signer, recipient, CYSToken, sdkmath.NewInt(1)); err != nil {
+			t.Fatalf("send: %v", err)
+		}
+	}
+	broadcasts := func() []int {
+		counts := make([]int, len(nodes))
+		for i, node := range nodes {
+			counts[i] = node.callCount(broadcastMethod)
+		}
+		return counts
+	}
+
+	send()
+	if got := broadcasts(); got[0] != 1 {
+		t.Fatalf("broadcasts per endpoint = %v, want the first endpoint", got)
+	}
+
+	// still usable but no longer the best, the account stays on its node
+	nodes[0].setLag(3)
+	s.pool.checkAll()
+	send()
+	send()
+	if got := broadcasts(); got[0] != 3 || got[1] != 0 || got[2] != 0 {
+		t.Fatalf("broadcasts per endpoint = %v, want all on the pinned endpoint", got)
+	}
+
+	// the pinned node goes down: the attempt fails over and the account is pinned elsewhere
+	nodes[0].srv.Stop()
+	send()
+	send()
+	if got := broadcasts(); got[1]+got[2] != 2 || (got[1] != 0 && got[2] != 0) {
+		t.Fatalf("broadcasts per endpoint = %v, want both on one new endpoint", got)
+	}
+
+	if got := chain.account(signer.Address()).sequence; got != 5 {
+		t.Errorf("sequence = %d, want 5", got)
+	}
+	if st := s.EndpointStatuses()[0]; st.Healthy || st.LastError == nil {
+		t.Errorf("stopped pinned endpoint: %+v, want unhealthy with an error", st)
+	}
+}
@@ -0,0 +1,287 @@
+package gosdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// The remote signing protocol is JSON over HTTP:
+//
+//	GET  {endpoint}/pubkey?address={bech32}  -> remoteKeyResponse
+//	POST {endpoint}/sign    remoteSignRequest -> remoteSignResponse
+//
+// Failures are answered with a non-2xx status and a remoteErrorResponse. The public key is
+// the Cosmos JSON encoding of the key, e.g. {"@type":"/cysicmint.crypto.v1.ethsecp256k1.PubKey","key":"..."}.
+const (
+	remotePubKeyPath = "/pubkey"
+	remoteSignPath   = "/sign"
+	// remoteMaxBodySize bounds the requests and responses of the protocol.
+	remoteMaxBodySize = 1 << 20
+)
+
+type remoteKeyResponse struct {
+	Address   string          `json:"address"`
+	PubKey    json.RawMessage `json:"pub_key"`
+	SignModes []string        `json:"sign_modes"`
+}
+
+type remoteSignRequest struct {
+	Address   string `json:"address"`
+	SignBytes []byte `json:"sign_bytes"`
+}
+
+type remoteSignResponse struct {
+	Signature []byte `json:"signature"`
+}
+
+type remoteErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// RemoteSignerOption customizes a RemoteSigner.
+type RemoteSignerOption func(*RemoteSigner)
+
+// WithRemoteSignerHTTPClient sets the HTTP client, e.g. one with mutual TLS; http.DefaultClient by default.
+//
+// @param client the HTTP client
+func WithRemoteSignerHTTPClient(client *http.Client) RemoteSignerOption {
+	return func(r *RemoteSigner) {
+		r.client = client
+	}
+}
+
+// WithRemoteSignerHeaders adds headers, e.g. an authorization token, to every request.
+//
+// @param headers the header names and values
+func WithRemoteSignerHeaders(headers map[string]string) RemoteSignerOption {
+	return func(r *RemoteSigner) {
+		for name, value := range headers {
+			r.headers[name] = value
+		}
+	}
+}
+
+// RemoteSigner is a TxSigner whose key is held by a signing service, such as a KMS proxy or a
+// process started with NewRemoteSignerHandler. It is safe for concurrent use.
+type RemoteSigner struct {
+	endpoint  string
+	client    *http.Client
+	headers   map[string]string
+	address   sdk.AccAddress
+	pubKey    types.PubKey
+	signModes []TxSignMode
+}
+
+// NewRemoteSigner connects to the signing service at endpoint and fetches the public key of address.
+//
+// @param ctx the context controlling cancellation and deadline of the key request
+// @param endpoint the base URL of the service, e.g. "https://signer.internal:8443"
+// @param address the hex or bech32 address of the key
+// @param opts the options of the signer
+// @return the remote signer, or an error if the service does not hold the key
+func NewRemoteSigner(ctx context.Context, endpoint string, address string, opts ...RemoteSignerOption) (*RemoteSigner, error) {
+	accAddr, err := toAccAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &RemoteSigner{
+		endpoint: strings.TrimRight(endpoint, "/"),
+		client:   http.DefaultClient,
+		headers:  make(map[string]string),
+		address:  accAddr,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	var resp remoteKeyResponse
+	if err := r.do(ctx, http.MethodGet, remotePubKeyPath+"?address="+accAddr.String(), nil, &resp); err != nil {
+		logger().Error("error when get remote pubkey", "address", accAddr.String(), "err", err)
+		return nil, err
+	}
+
+	var pubKey types.PubKey
+	if err := cdc.UnmarshalInterfaceJSON(resp.PubKey, &pubKey); err != nil {
+		return nil, fmt.Errorf("invalid remote public key: %w", err)
+	}
+	if !sdk.AccAddress(pubKey.Address()).Equals(accAddr) {
+		return nil, fmt.Errorf("remote public key belongs to %v, not %v", sdk.AccAddress(pubKey.Address()).String(), accAddr.String())
+	}
+	for _, name := range resp.SignModes {
+		mode, err := parseTxSignMode(name)
+		if err != nil {
+			return nil, err
+		}
+		r.signModes = append(r.signModes, mode)
+	}
+	r.pubKey = pubKey
+
+	return r, nil
+}
+
+// Address returns the address of the remote key.
+func (r *RemoteSigner) Address() sdk.AccAddress {
+	return r.address
+}
+
+// PubKey returns the public key of the remote key.
+func (r *RemoteSigner) PubKey() types.PubKey {
+	return r.pubKey
+}
+
+// SignModes returns the sign modes the service announced for the key.
+func (r *RemoteSigner) SignModes() []TxSignMode {
+	return r.signModes
+}
+
+// SignBytes asks the service to sign msg.
+//
+// @param ctx the context controlling cancellation and deadline of the request
+// @param msg the bytes to sign
+// @return the signature, or an error if the service refuses or can't be reached
+func (r *RemoteSigner) SignBytes(ctx context.Context, msg []byte) ([]byte, error) {
+	var resp remoteSignResponse
+	req := remoteSignRequest{Address: r.address.String(), SignBytes: msg}
+	if err := r.do(ctx, http.MethodPost, remoteSignPath, req, &resp); err != nil {
+		logger().Error("error when remote sign", "address", r.address.String(), "err", err)
+		return nil, err
+	}
+	if len(resp.Signature) == 0 {
+		return nil, fmt.Errorf("remote signer returned an empty signature")
+	}
+
+	return resp.Signature, nil
+}
+
+// do sends one request of the protocol and decodes the response into out.
+func (r *RemoteSigner) do(ctx context.Context, method string, path string, in interface{}, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		bz, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(bz)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, r.endpoint+path, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, value := range r.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return wrapCtxErr(ctx, err)
+	}
+	defer resp.Body.Close()
+
+	bz, err := io.ReadAll(io.LimitReader(resp.Body, remoteMaxBodySize))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var errResp remoteErrorResponse
+		if json.Unmarshal(bz, &errResp) == nil && errResp.Error != "" {
+			return fmt.Errorf("remote signer: %v: %v", resp.Status, errResp.Error)
+		}
+		return fmt.Errorf("remote signer: %v", resp.Status)
+	}
+
+	return json.Unmarshal(bz, out)
+}
+
+// NewRemoteSignerHandler serves the remote signing protocol for signers, so that a process
+// holding keys, or a test, can stand in for a signing service. It signs everything it is
+// asked to: put it behind authentication.
+//
+// @param signers the signers whose keys are served
+// @return the HTTP handler
+func NewRemoteSignerHandler(signers ...TxSigner) http.Handler {
+	byAddress := make(map[string]TxSigner, len(signers))
+	for _, signer := range signers {
+		byAddress[signer.Address().String()] = signer
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(remotePubKeyPath, func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			writeRemoteError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %v not allowed", req.Method))
+			return
+		}
+
+		signer, ok := byAddress[req.URL.Query().Get("address")]
+		if !ok {
+			writeRemoteError(w, http.StatusNotFound, fmt.Errorf("unknown address %v", req.URL.Query().Get("address")))
+			return
+		}
+
+		pubKey, err := cdc.MarshalInterfaceJSON(signer.PubKey())
+		if err != nil {
+			writeRemoteError(w, http.StatusInternalServerError, err)
+			return
+		}
+		resp := remoteKeyResponse{Address: signer.Address().String(), PubKey: pubKey}
+		for _, mode := range signer.SignModes() {
+			resp.SignModes = append(resp.SignModes, mode.String())
+		}
+		writeRemoteJSON(w, resp)
+	})
+	mux.HandleFunc(remoteSignPath, func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			writeRemoteError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %v not allowed", req.Method))
+			return
+		}
+
+		var signReq remoteSignRequest
+		if err := json.NewDecoder(io.LimitReader(req.Body, remoteMaxBodySize)).Decode(&signReq); err != nil {
+			writeRemoteError(w, http.StatusBadRequest, err)
+			return
+		}
+		signer, ok := byAddress[signReq.Address]
+		if !ok {
+			writeRemoteError(w, http.StatusNotFound, fmt.Errorf("unknown address %v", signReq.Address))
+			return
+		}
+
+		sig, err := signer.SignBytes(req.Context(), signReq.SignBytes)
+		if err != nil {
+			writeRemoteError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeRemoteJSON(w, remoteSignResponse{Signature: sig})
+	})
+
+	return mux
+}
+
+// writeRemoteJSON writes a successful response of the protocol.
+func writeRemoteJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger().Error("error when write remote signer response", "err", err)
+	}
+}
+
+// writeRemoteError writes a failed response of the protocol.
+func writeRemoteError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(remoteErrorResponse{Error: err.Error()}); err != nil {
+		logger().Error("error when write remote signer response", "err", err)
+	}
+}
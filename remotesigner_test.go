@@ -0,0 +1,128 @@
+package gosdk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	sdkmath "cosmossdk.io/math"
+)
+
+// newTestSignerService serves the keys of signers over the remote signing protocol, answering
+// 401 to requests without the bearer token "secret"; sign, if not nil, replaces the /sign handler.
+func newTestSignerService(t *testing.T, sign http.HandlerFunc, signers ...TxSigner) *httptest.Server {
+	t.Helper()
+
+	handler := NewRemoteSignerHandler(signers...)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer secret" {
+			writeRemoteError(w, http.StatusUnauthorized, errors.New("missing token"))
+			return
+		}
+		if sign != nil && req.URL.Path == remoteSignPath {
+			sign(w, req)
+			return
+		}
+		handler.ServeHTTP(w, req)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestRemoteSignerBroadcast(t *testing.T) {
+	chain := newMockChain()
+	node := chain.serve(t)
+	s := newTestServer(t, node)
+
+	local := newTestSigner(t)
+	srv := newTestSignerService(t, nil, local)
+	chain.fund(local.Address(), testCoins(1_000_000))
+	recipient := newTestSigner(t).Address()
+
+	token := WithRemoteSignerHeaders(map[string]string{"Authorization": "Bearer secret"})
+	remote, err := NewRemoteSigner(context.Background(), srv.URL+"/", local.EthAddr.Hex(), token)
+	if err != nil {
+		t.Fatalf("new remote signer: %v", err)
+	}
+	if !remote.Address().Equals(local.Address()) || !remote.PubKey().Equals(local.PubKey()) {
+		t.Errorf("remote key %v differs from the served %v", remote.Address(), local.Address())
+	}
+	if modes := remote.SignModes(); len(modes) != 2 || modes[0] != SignModeDirect || modes[1] != SignModeEIP712 {
+		t.Errorf("sign modes = %v, want direct and eip712", modes)
+	}
+
+	if _, err := s.Send(remote, recipient.String(), CYSToken, sdkmath.NewInt(100)); err != nil {
+		t.Fatalf("send with the remote signer: %v", err)
+	}
+	if got := chain.account(recipient).balance; !got.IsEqual(testCoins(100)) {
+		t.Errorf("recipient balance = %v, want %v", got, testCoins(100))
+	}
+	if got := chain.account(local.Address()).sequence; got != 1 {
+		t.Errorf("sequence = %d, want 1", got)
+	}
+
+	// the public key travels in its Cosmos JSON encoding
+	req, err := http.NewRequest(http.MethodGet, srv.URL+remotePubKeyPath+"?address="+local.Address().String(), nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("get pubkey: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read pubkey: %v", err)
+	}
+	if !strings.Contains(string(body), `"@type":"/cysicmint.crypto.v1.ethsecp256k1.PubKey"`) {
+		t.Errorf("pubkey response %s, want an ethsecp256k1 key", body)
+	}
+
+	if _, err := NewRemoteSigner(context.Background(), srv.URL, local.EthAddr.Hex()); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("without the token: got %v, want a 401 error", err)
+	}
+	if _, err := NewRemoteSigner(context.Background(), srv.URL, recipient.String(), token); err == nil || !strings.Contains(err.Error(), "unknown address") {
+		t.Errorf("address the service does not hold: got %v, want an unknown address error", err)
+	}
+}
+
+func TestRemoteSignerSignError(t *testing.T) {
+	chain := newMockChain()
+	node := chain.serve(t)
+	s := newTestServer(t, node)
+
+	local := newTestSigner(t)
+	refuse := func(w http.ResponseWriter, _ *http.Request) {
+		writeRemoteError(w, http.StatusForbidden, errors.New("policy denies the transaction"))
+	}
+	srv := newTestSignerService(t, refuse, local)
+	chain.fund(local.Address(), testCoins(1_000_000))
+
+	remote, err := NewRemoteSigner(context.Background(), srv.URL, local.Address().String(), WithRemoteSignerHeaders(map[string]string{"Authorization": "Bearer secret"}))
+	if err != nil {
+		t.Fatalf("new remote signer: %v", err)
+	}
+
+	_, err = remote.SignBytes(context.Background(), []byte("msg"))
+	if err == nil || !strings.Contains(err.Error(), "403 Forbidden: policy denies the transaction") {
+		t.Errorf("sign: got %v, want the error of the service", err)
+	}
+
+	_, err = s.Send(remote, newTestSigner(t).Address().String(), CYSToken, sdkmath.NewInt(1))
+	if err == nil || !strings.Contains(err.Error(), "policy denies the transaction") {
+		t.Errorf("send: got %v, want the error of the service", err)
+	}
+	if n := node.callCount(broadcastMethod); n != 0 {
+		t.Errorf("%d broadcasts, want none", n)
+	}
+	if got := chain.account(local.Address()).sequence; got != 0 {
+		t.Errorf("sequence = %d, want 0", got)
+	}
+}
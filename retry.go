@@ -0,0 +1,140 @@
+package gosdk
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"time"
+
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RetryPolicy controls how transient failures of queries and broadcasts are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one. Values below 2 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the wait before the second attempt.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between two attempts.
+	MaxBackoff time.Duration
+	// Multiplier grows the wait after every failed attempt.
+	Multiplier float64
+	// Jitter randomizes every wait by up to ±Jitter of its value, between 0 and 1.
+	Jitter float64
+	// Retryable classifies errors; nil means IsRetryableError.
+	Retryable func(err error) bool
+}
+
+// DefaultRetryPolicy returns the policy a Server uses unless WithRetryPolicy is given:
+// 3 attempts, starting at 200ms and doubling up to 2s, with 20% jitter.
+//
+// @return the default retry policy
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     2 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// NoRetry returns a policy that never retries.
+//
+// @return the retry policy
+func NoRetry() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 1}
+}
+
+// IsRetryableError reports whether err is transient: gRPC Unavailable or ResourceExhausted,
+// a full mempool, or an account sequence mismatch. Failures such as insufficient funds or
+// fees, out of gas or invalid messages are never retryable.
+//
+// @param err the error to classify
+// @return true if the operation may succeed when attempted again
+func IsRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var txErr *TxError
+	if errors.As(err, &txErr) {
+		return txErr.is(sdkerrors.ErrMempoolIsFull) || txErr.is(sdkerrors.ErrWrongSequence)
+	}
+
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted:
+		return true
+	}
+
+	return false
+}
+
+func (p RetryPolicy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+
+	return IsRetryableError(err)
+}
+
+// backoff returns the wait before the given attempt, counted from 1 for the first retry.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	wait := float64(p.InitialBackoff)
+	if p.Multiplier > 1 {
+		wait *= math.Pow(p.Multiplier, float64(attempt-1))
+	}
+	if p.MaxBackoff > 0 && wait > float64(p.MaxBackoff) {
+		wait = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		wait += wait * p.Jitter * (2*rand.Float64() - 1) // #nosec G404 -- jitter needs no crypto randomness
+	}
+
+	return time.Duration(wait)
+}
+
+// do runs fn until it succeeds, returns a non-retryable error, the attempts are exhausted or ctx is done.
+//
+// @param ctx the context bounding all attempts and waits
+// @param logger the Logger retries are logged to
+// @param op the operation name used in logs
+// @param fn the operation; it is called at least once
+// @return the error of the last attempt
+func (p RetryPolicy) do(ctx context.Context, logger Logger, op string, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= p.MaxAttempts || !p.retryable(err) {
+			return err
+		}
+
+		wait := p.backoff(attempt)
+		logger.Warn("operation failed, retrying", "method", op, "attempt", attempt, "backoff", wait, "err", err)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return wrapCtxErr(ctx, err)
+		case <-timer.C:
+		}
+	}
+}
+
+// retryConn retries unary calls on transient gRPC errors according to a RetryPolicy.
+type retryConn struct {
+	grpc.ClientConnInterface
+	policy RetryPolicy
+	logger Logger
+}
+
+// Invoke performs the unary call, retrying it with backoff while the error is retryable.
+func (c *retryConn) Invoke(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+	return c.policy.do(ctx, c.logger, method, func() error {
+		return c.ClientConnInterface.Invoke(ctx, method, args, reply, opts...)
+	})
+}
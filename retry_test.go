@@ -0,0 +1,144 @@
+package gosdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// rejectedTx returns the error of a transaction the chain rejected with the root error registered.
+func rejectedTx(registered interface{ ABCICode() uint32 }) error {
+	return TxResponseError(&sdk.TxResponse{TxHash: "ABCD", Codespace: sdkerrors.RootCodespace, Code: registered.ABCICode(), RawLog: "rejected"})
+}
+
+func TestIsRetryableError(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"mempool full", rejectedTx(sdkerrors.ErrMempoolIsFull), true},
+		{"wrong sequence", rejectedTx(sdkerrors.ErrWrongSequence), true},
+		{"wrapped wrong sequence", fmt.Errorf("broadcast: %w", rejectedTx(sdkerrors.ErrWrongSequence)), true},
+		{"unavailable", status.Error(codes.Unavailable, "connection refused"), true},
+		{"resource exhausted", status.Error(codes.ResourceExhausted, "rate limited"), true},
+		{"insufficient funds", rejectedTx(sdkerrors.ErrInsufficientFunds), false},
+		{"insufficient fee", rejectedTx(sdkerrors.ErrInsufficientFee), false},
+		{"out of gas", rejectedTx(sdkerrors.ErrOutOfGas), false},
+		{"unauthorized", rejectedTx(sdkerrors.ErrUnauthorized), false},
+		{"other codespace", TxResponseError(&sdk.TxResponse{Codespace: "bank", Code: sdkerrors.ErrMempoolIsFull.ABCICode()}), false},
+		{"invalid argument", status.Error(codes.InvalidArgument, "bad request"), false},
+		{"deadline", status.Error(codes.DeadlineExceeded, "deadline exceeded"), false},
+		{"plain error", errors.New("boom"), false},
+	}
+	for _, c := range cases {
+		if got := IsRetryableError(c.err); got != c.want {
+			t.Errorf("%v: IsRetryableError(%v) = %v, want %v", c.name, c.err, got, c.want)
+		}
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2}
+	for attempt, want := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		4: 800 * time.Millisecond,
+		5: time.Second,
+		9: time.Second,
+	} {
+		if got := policy.backoff(attempt); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+
+	policy.Jitter = 0.2
+	for attempt := 1; attempt <= 8; attempt++ {
+		base := RetryPolicy{InitialBackoff: policy.InitialBackoff, MaxBackoff: policy.MaxBackoff, Multiplier: policy.Multiplier}.backoff(attempt)
+		low, high := time.Duration(float64(base)*0.8), time.Duration(float64(base)*1.2)
+		for i := 0; i < 100; i++ {
+			if got := policy.backoff(attempt); got < low || got > high {
+				t.Fatalf("backoff(%d) = %v, want within %v and %v", attempt, got, low, high)
+			}
+		}
+	}
+}
+
+func TestRetryPolicyDo(t *testing.T) {
+	policy := fastRetry()
+	policy.MaxAttempts = 4
+	unavailable := status.Error(codes.Unavailable, "connection refused")
+
+	calls := 0
+	err := policy.do(context.Background(), NopLogger(), "test", func() error {
+		calls++
+		return unavailable
+	})
+	if calls != 4 || !errors.Is(err, unavailable) {
+		t.Errorf("always failing: %d calls, %v; want 4 calls and the last error", calls, err)
+	}
+
+	calls = 0
+	err = policy.do(context.Background(), NopLogger(), "test", func() error {
+		calls++
+		if calls < 3 {
+			return unavailable
+		}
+		return nil
+	})
+	if calls != 3 || err != nil {
+		t.Errorf("failing twice: %d calls, %v; want 3 calls and no error", calls, err)
+	}
+
+	calls = 0
+	insufficientFunds := rejectedTx(sdkerrors.ErrInsufficientFunds)
+	err = policy.do(context.Background(), NopLogger(), "test", func() error {
+		calls++
+		return insufficientFunds
+	})
+	if calls != 1 || !errors.Is(err, ErrInsufficientFunds) {
+		t.Errorf("not retryable: %d calls, %v; want 1 call and the error", calls, err)
+	}
+
+	calls = 0
+	err = NoRetry().do(context.Background(), NopLogger(), "test", func() error {
+		calls++
+		return unavailable
+	})
+	if calls != 1 || !errors.Is(err, unavailable) {
+		t.Errorf("no retry: %d calls, %v; want 1 call", calls, err)
+	}
+}
+
+func TestRetryPolicyDoCanceled(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, InitialBackoff: time.Hour, MaxBackoff: time.Hour}
+	ctx, cancel := context.WithCancel(context.Background())
+	unavailable := status.Error(codes.Unavailable, "connection refused")
+
+	calls := 0
+	done := make(chan error)
+	go func() {
+		done <- policy.do(ctx, NopLogger(), "test", func() error {
+			calls++
+			return unavailable
+		})
+	}()
+	cancel()
+
+	select {
+	case err := <-done:
+		if calls != 1 || !errors.Is(err, context.Canceled) || !errors.Is(err, unavailable) {
+			t.Errorf("canceled: %d calls, %v; want 1 call and the cancellation with the last error", calls, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("do did not return when the context was canceled")
+	}
+}
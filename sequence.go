@@ -0,0 +1,264 @@
+package gosdk
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// expectedSequenceRegex extracts the expected sequence from the sdk's
+// "account sequence mismatch, expected X, got Y" CheckTx log.
+var expectedSequenceRegex = regexp.MustCompile(`account sequence mismatch, expected (\d+), got (\d+)`)
+
+// BroadcastResult describes a transaction accepted into the mempool.
+type BroadcastResult struct {
+	TxHash        string
+	AccountNumber uint64
+	Sequence      uint64
+}
+
+// PendingTx is a transaction broadcast through the SequenceManager whose sequence the chain
+// was not yet seen to have committed. The chain is seen to commit a sequence when the account
+// is read from chain, by GetAccount or a resync, or when AwaitTx finds a transaction included.
+type PendingTx struct {
+	Sequence uint64
+	TxHash   string
+}
+
+// SequenceManager allocates account sequences locally so that one account can have many
+// transactions in the mempool at once, instead of querying the committed sequence before
+// every transaction.
+//
+// Sequences are allocated and broadcast under a per-account lock, so transactions of one
+// account reach the mempool in sequence order. On a sequence mismatch the manager adopts
+// the sequence expected by the node, or re-reads the account from chain.
+type SequenceManager struct {
+	mu       sync.Mutex
+	accounts map[string]*accountSequence
+}
+
+// accountSequence is the local sequence state of one account. mu is held while a transaction
+// of the account is built and broadcast; pendingMu guards inFlight only, so that observing the
+// chain doesn't wait for a broadcast in progress.
+type accountSequence struct {
+	mu            sync.Mutex
+	synced        bool
+	accountNumber uint64
+	next          uint64
+
+	pendingMu sync.Mutex
+	inFlight  map[uint64]string
+}
+
+// NewSequenceManager creates an empty SequenceManager.
+//
+// @return the sequence manager
+func NewSequenceManager() *SequenceManager {
+	return &SequenceManager{accounts: make(map[string]*accountSequence)}
+}
+
+// account returns the state of address, creating it unsynced on first use.
+func (m *SequenceManager) account(address string) *accountSequence {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	acc, ok := m.accounts[address]
+	if !ok {
+		acc = &accountSequence{inFlight: make(map[uint64]string)}
+		m.accounts[address] = acc
+	}
+
+	return acc
+}
+
+// lock locks the states of addresses in sorted order, so that transactions sharing accounts
+// can't deadlock, and returns them in the order of addresses with the function unlocking them.
+// Without a manager the states are nil.
+func (m *SequenceManager) lock(addresses ...string) ([]*accountSequence, func()) {
+	states := make([]*accountSequence, len(addresses))
+	if m == nil {
+		return states, func() {}
+	}
+
+	for i, address := range addresses {
+		states[i] = m.account(address)
+	}
+
+	sorted := make([]string, 0, len(addresses))
+	seen := make(map[string]bool, len(addresses))
+	for _, address := range addresses {
+		if !seen[address] {
+			seen[address] = true
+			sorted = append(sorted, address)
+		}
+	}
+	sort.Strings(sorted)
+
+	locked := make([]*accountSequence, 0, len(sorted))
+	for _, address := range sorted {
+		acc := m.account(address)
+		acc.mu.Lock()
+		locked = append(locked, acc)
+	}
+
+	return states, func() {
+		for i := len(locked) - 1; i >= 0; i-- {
+			locked[i].mu.Unlock()
+		}
+	}
+}
+
+// Pending returns the in-flight transactions of address, ordered by sequence.
+//
+// @param address the bech32 address of the account
+// @return the in-flight transactions
+func (m *SequenceManager) Pending(address string) []PendingTx {
+	acc := m.account(address)
+	acc.pendingMu.Lock()
+	defer acc.pendingMu.Unlock()
+
+	result := make([]PendingTx, 0, len(acc.inFlight))
+	for sequence, txHash := range acc.inFlight {
+		result = append(result, PendingTx{Sequence: sequence, TxHash: txHash})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Sequence < result[j].Sequence })
+
+	return result
+}
+
+// Reset forgets everything known about address; the next transaction re-reads the account from chain.
+//
+// @param address the bech32 address of the account
+func (m *SequenceManager) Reset(address string) {
+	acc := m.account(address)
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+
+	acc.synced = false
+	acc.pendingMu.Lock()
+	acc.inFlight = make(map[uint64]string)
+	acc.pendingMu.Unlock()
+}
+
+// observe drops the in-flight entries of address below sequence, the on-chain sequence of
+// the account. Unlike sync it doesn't touch the next sequence, so it may run while a
+// transaction of the account is being broadcast.
+//
+// @param address the bech32 address of the account
+// @param sequence the sequence read from chain
+func (m *SequenceManager) observe(address string, sequence uint64) {
+	if m == nil {
+		return
+	}
+
+	m.account(address).prune(sequence)
+}
+
+// included drops the in-flight entry of txHash, which the chain has included in a block,
+// together with the entries of lower sequences of the same accounts.
+//
+// @param txHash the hash of the included transaction
+func (m *SequenceManager) included(txHash string) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	accounts := make([]*accountSequence, 0, len(m.accounts))
+	for _, acc := range m.accounts {
+		accounts = append(accounts, acc)
+	}
+	m.mu.Unlock()
+
+	for _, acc := range accounts {
+		acc.pendingMu.Lock()
+		for seq, hash := range acc.inFlight {
+			if strings.EqualFold(hash, txHash) {
+				acc.pruneLocked(seq + 1)
+				break
+			}
+		}
+		acc.pendingMu.Unlock()
+	}
+}
+
+// sync adopts the on-chain account state and drops in-flight entries the chain has committed.
+// Callers must hold acc.mu.
+func (acc *accountSequence) sync(accountNumber, sequence uint64) {
+	acc.synced = true
+	acc.accountNumber = accountNumber
+	acc.next = sequence
+	acc.prune(sequence)
+}
+
+// prune drops the in-flight entries below sequence, which the chain has committed.
+func (acc *accountSequence) prune(sequence uint64) {
+	acc.pendingMu.Lock()
+	defer acc.pendingMu.Unlock()
+
+	acc.pruneLocked(sequence)
+}
+
+// pruneLocked is prune for callers holding acc.pendingMu.
+func (acc *accountSequence) pruneLocked(sequence uint64) {
+	for seq := range acc.inFlight {
+		if seq < sequence {
+			delete(acc.inFlight, seq)
+		}
+	}
+}
+
+// commit records that sequence was accepted into the mempool. Callers must hold acc.mu.
+func (acc *accountSequence) commit(sequence uint64, txHash string) {
+	acc.pendingMu.Lock()
+	acc.inFlight[sequence] = txHash
+	acc.pendingMu.Unlock()
+	if sequence >= acc.next {
+		acc.next = sequence + 1
+	}
+}
+
+// mismatch handles a sequence mismatch reported in rawLog. If the node's expected sequence
+// can be parsed it is adopted, otherwise the account is re-read from chain on next use.
+// Callers must hold acc.mu.
+func (acc *accountSequence) mismatch(rawLog string) {
+	expected, ok := parseExpectedSequence(rawLog)
+	if !ok {
+		acc.synced = false
+		return
+	}
+
+	acc.next = expected
+	acc.pendingMu.Lock()
+	for seq := range acc.inFlight {
+		if seq >= expected {
+			delete(acc.inFlight, seq)
+		}
+	}
+	acc.pendingMu.Unlock()
+}
+
+// desync makes the next transaction re-read the account from chain. Callers must hold acc.mu.
+func (acc *accountSequence) desync() {
+	acc.synced = false
+}
+
+// parseExpectedSequence extracts the sequence a node expected from a mismatch log.
+//
+// @param rawLog the raw log of the rejected transaction
+// @return the expected sequence and whether it could be parsed
+func parseExpectedSequence(rawLog string) (uint64, bool) {
+	matches := expectedSequenceRegex.FindStringSubmatch(rawLog)
+	if len(matches) != 3 {
+		return 0, false
+	}
+
+	expected, err := strconv.ParseUint(matches[1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return expected, true
+}
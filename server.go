@@ -1,33 +1,114 @@
 package gosdk
 
 import (
-	"log"
+	"context"
+	"sync"
 
 	"github.com/cosmos/cosmos-sdk/codec"
 	codecTypes "github.com/cosmos/cosmos-sdk/codec/types"
 	"github.com/cosmos/cosmos-sdk/types/tx/signing"
 	"github.com/cosmos/cosmos-sdk/x/auth/tx"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/connectivity"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 var (
 	interfaceRegistry = codecTypes.NewInterfaceRegistry()
 	cdc               = codec.NewProtoCodec(interfaceRegistry)
+	legacyAmino       = codec.NewLegacyAmino()
 	txConfig          = tx.NewTxConfig(cdc, tx.DefaultSignModes)
 
 	gasLimit = uint64(15_000_000)
 	signMode = signing.SignMode_SIGN_MODE_DIRECT
 )
 
+// Server is a client of a Cysic chain.
+//
+// A Server is safe for concurrent use by multiple goroutines. Its endpoint, chain ID and
+// connection options are fixed at construction; the fee settings can only be changed
+// through SetGas and SetGasLimit.
 type Server struct {
-	EndPoint string
-	Conn     *grpc.ClientConn
-	ChainID  string
-	GasCoin  string
-	GasPrice int64
-	GasLimit uint64
+	endPoint    string
+	chainID     string
+	pool        *connPool
+	retryPolicy RetryPolicy
+	sequences   *SequenceManager
+	logger      Logger
+	tracer      trace.Tracer
+	metrics     *Metrics
+
+	autoGas       bool
+	gasAdjustment float64
+	gasFloor      uint64
+	gasCeiling    uint64
+	feeStrategy   FeeStrategy
+	signMode      TxSignMode
+
+	mu       sync.RWMutex
+	gasCoin  string
+	gasPrice int64
+	gasLimit uint64
+}
+
+// gasConfig is a consistent snapshot of the fee settings of a Server.
+type gasConfig struct {
+	coin  string
+	price int64
+	limit uint64
+}
+
+// NewServer creates a new Server instance configured with functional options.
+//
+// Without options the Server dials insecurely, pays fees in CYS at a gas price of zero and
+// estimates gas by simulation, capped at the default gas limit; see WithGas, WithSystemTLS,
+// WithKeepalive and friends.
+//
+// @param endPoint the endpoint of the gRPC server
+// @param chainID the chain ID of the blockchain
+// @param opts the options to apply
+// @return a new Server instance, or an error if an option is invalid or the connection fails
+func NewServer(endPoint string, chainID string, opts ...Option) (*Server, error) {
+	o := defaultServerOptions()
+	for _, opt := range opts {
+		if err := opt(o); err != nil {
+			return nil, err
+		}
+	}
+
+	endpoints := append([]string{endPoint}, o.endpoints...)
+	pool, err := newConnPool(context.Background(), endpoints, o.grpcDialOptions(chainID), o.maxHeightLag, o.logger)
+	if err != nil {
+		o.logger.Error("error when new grpc client", "endpoint", endPoint, "err", err)
+		return nil, err
+	}
+	if len(endpoints) > 1 && o.healthCheckInterval > 0 {
+		pool.startHealthCheck(o.healthCheckInterval)
+	}
+
+	s := &Server{
+		endPoint:    endPoint,
+		chainID:     chainID,
+		pool:        pool,
+		retryPolicy: o.retryPolicy,
+		logger:      o.logger,
+		tracer:      o.tracerProvider.Tracer(instrumentationName),
+		metrics:     o.metrics,
+		gasCoin:     o.gasCoin,
+		gasPrice:    o.gasPrice,
+		gasLimit:    o.gasLimit,
+
+		autoGas:       o.autoGas,
+		gasAdjustment: o.gasAdjustment,
+		gasFloor:      o.gasFloor,
+		gasCeiling:    o.gasCeiling,
+		feeStrategy:   o.feeStrategy,
+		signMode:      o.signMode,
+	}
+	if o.localSequences {
+		s.sequences = NewSequenceManager()
+	}
+
+	return s, nil
 }
 
 // NewServerWithGRPC creates a new Server instance with a gRPC connection.
@@ -38,20 +119,7 @@ type Server struct {
 // @param gasPrice the price of gas
 // @return a new Server instance, or an error if the connection fails
 func NewServerWithGRPC(endPoint string, chainID string, gasCoin string, gasPrice int64) (*Server, error) {
-	conn, err := grpc.Dial(endPoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		log.Printf("error when new grpc client: %s\n", err.Error())
-		return nil, err
-	}
-
-	return &Server{
-		EndPoint: endPoint,
-		ChainID:  chainID,
-		GasPrice: gasPrice,
-		GasCoin:  gasCoin,
-		GasLimit: gasLimit,
-		Conn:     conn,
-	}, nil
+	return NewServer(endPoint, chainID, WithGas(gasCoin, gasPrice))
 }
 
 // NewServerWithGRPCAndGasLimit creates a new Server instance with a gRPC connection and custom gas limit.
@@ -63,37 +131,142 @@ func NewServerWithGRPC(endPoint string, chainID string, gasCoin string, gasPrice
 // @param _gasLimit the custom gas limit
 // @return a new Server instance, or an error if the connection fails
 func NewServerWithGRPCAndGasLimit(endPoint string, chainID string, gasCoin string, gasPrice int64, _gasLimit uint64) (*Server, error) {
-	conn, err := grpc.Dial(endPoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		log.Printf("error when new grpc client: %s\n", err.Error())
-		return nil, err
+	return NewServer(endPoint, chainID, WithGas(gasCoin, gasPrice), WithGasLimit(_gasLimit))
+}
+
+// EndPoint returns the primary gRPC endpoint of the Server.
+func (s *Server) EndPoint() string {
+	return s.endPoint
+}
+
+// ChainID returns the chain ID transactions are signed for.
+func (s *Server) ChainID() string {
+	return s.chainID
+}
+
+// Conn returns the connection to the primary endpoint.
+func (s *Server) Conn() *grpc.ClientConn {
+	return s.pool.primary()
+}
+
+// GasCoin returns the coin fees are paid in.
+func (s *Server) GasCoin() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.gasCoin
+}
+
+// GasPrice returns the gas price used to compute fees.
+func (s *Server) GasPrice() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.gasPrice
+}
+
+// GasLimit returns the gas limit set on transactions.
+func (s *Server) GasLimit() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.gasLimit
+}
+
+// SetGas changes the coin and price used to pay fees for subsequent transactions.
+//
+// @param gasCoin the coin to use for gas fees
+// @param gasPrice the price of gas
+// @return an error if the values are invalid
+func (s *Server) SetGas(gasCoin string, gasPrice int64) error {
+	o := &serverOptions{}
+	if err := WithGas(gasCoin, gasPrice)(o); err != nil {
+		return err
 	}
 
-	return &Server{
-		EndPoint: endPoint,
-		ChainID:  chainID,
-		GasPrice: gasPrice,
-		GasCoin:  gasCoin,
-		GasLimit: _gasLimit,
-		Conn:     conn,
-	}, nil
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gasCoin = o.gasCoin
+	s.gasPrice = o.gasPrice
+	return nil
 }
 
-func (s *Server) KeepGrpcConn() error {
-	if s.Conn != nil && s.Conn.GetState() != connectivity.Shutdown {
-		return nil
+// SetGasLimit changes the gas limit for subsequent transactions.
+//
+// @param limit the gas limit
+// @return an error if the limit is invalid
+func (s *Server) SetGasLimit(limit uint64) error {
+	o := &serverOptions{}
+	if err := WithGasLimit(limit)(o); err != nil {
+		return err
 	}
 
-	conn, err := grpc.Dial(s.EndPoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		log.Printf("error when new grpc client: %s\n", err.Error())
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gasLimit = o.gasLimit
+	return nil
+}
+
+// gas returns a consistent snapshot of the fee settings.
+func (s *Server) gas() gasConfig {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return gasConfig{coin: s.gasCoin, price: s.gasPrice, limit: s.gasLimit}
+}
+
+// KeepGrpcConn makes sure the Server holds a usable gRPC connection, re-dialing if it was shut down.
+//
+// @return an error if the connection could not be re-established
+func (s *Server) KeepGrpcConn() error {
+	return s.KeepGrpcConnCtx(context.Background())
+}
+
+// KeepGrpcConnCtx is like KeepGrpcConn but uses ctx for the re-dial.
+//
+// @param ctx the context controlling cancellation and deadline
+// @return an error if the connection could not be re-established
+func (s *Server) KeepGrpcConnCtx(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
 		return err
 	}
 
-	s.Conn = conn
+	if err := s.pool.reconnect(ctx); err != nil {
+		s.log().Error("error when new grpc client", "endpoint", s.endPoint, "err", err)
+		return wrapCtxErr(ctx, err)
+	}
+
 	return nil
 }
 
+// conn returns the connection queries should use: the endpoint pool, with retries.
+func (s *Server) conn() grpc.ClientConnInterface {
+	return &retryConn{ClientConnInterface: s.pool, policy: s.retryPolicy, logger: s.logger}
+}
+
+// accountConn returns the connection all transactions of address are sent through, so that
+// one account's sequences are always broadcast to the same endpoint. Calls on it are not
+// retried; the transaction pipeline retries as a whole.
+//
+// @param address the signer's address
+func (s *Server) accountConn(address string) grpc.ClientConnInterface {
+	return s.pool.pinned(address)
+}
+
+// SequenceManager returns the local sequence manager, or nil unless the Server was
+// created with WithLocalSequences.
+func (s *Server) SequenceManager() *SequenceManager {
+	return s.sequences
+}
+
+// EndpointStatuses returns the last known health of every configured endpoint.
+//
+// @return the endpoint statuses in configuration order
+func (s *Server) EndpointStatuses() []EndpointStatus {
+	return s.pool.statuses()
+}
+
+// Close stops health checking and closes every gRPC connection of the Server.
 func (s *Server) Close() error {
-	return s.Conn.Close()
+	return s.pool.close()
 }
@@ -0,0 +1,720 @@
+package gosdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cysic-tech/gosdk/crypto/ethsecp256k1"
+	cysicTypes "github.com/cysic-tech/gosdk/types/cysic"
+	feemarketTypes "github.com/cysic-tech/gosdk/types/feemarket"
+
+	errorsmod "cosmossdk.io/errors"
+	sdkmath "cosmossdk.io/math"
+	"github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+	codecTypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	sdkTx "github.com/cosmos/cosmos-sdk/types/tx"
+	authSigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	authTypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	bankTypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	testChainID = "cysicmint_9001-1"
+	// mockTxGas and mockMsgGas are the gas a transaction of the mock chain consumes.
+	mockTxGas  = uint64(30_000)
+	mockMsgGas = uint64(20_000)
+)
+
+// mockChain is the state of an in-process chain serving the auth, bank, fee market, tx and
+// tendermint services the Server uses. It runs the checks of the SDK ante handler that matter to the
+// client: account existence, sequences, direct-mode signatures, gas and fees, and executes
+// MsgSend. Fee allowances are not checked, and other messages are accepted without effect.
+type mockChain struct {
+	mu       sync.Mutex
+	height   int64
+	accounts map[string]*mockAccount
+	baseFee  *sdkmath.Int
+	// txs are the included transactions GetTx finds, held those included while indexing is paused.
+	txs      map[string]*sdk.TxResponse
+	txBytes  map[string][]byte
+	held     []*sdk.TxResponse
+	noIndex  bool
+	execFail error
+}
+
+// mockExecError is the error of a transaction that passed the ante handler but failed in
+// its messages: it is included in a block, with the fee and sequence of the ante handler applied.
+type mockExecError struct {
+	err error
+}
+
+func (e *mockExecError) Error() string { return e.err.Error() }
+
+func (e *mockExecError) Unwrap() error { return e.err }
+
+type mockAccount struct {
+	number   uint64
+	sequence uint64
+	balance  sdk.Coins
+}
+
+func newMockChain() *mockChain {
+	return &mockChain{height: 100, accounts: make(map[string]*mockAccount), txs: make(map[string]*sdk.TxResponse), txBytes: make(map[string][]byte)}
+}
+
+// fund creates the account of addr if needed and adds coins to its balance.
+func (c *mockChain) fund(addr sdk.AccAddress, coins sdk.Coins) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	acc, ok := c.accounts[addr.String()]
+	if !ok {
+		acc = &mockAccount{number: uint64(len(c.accounts))}
+		c.accounts[addr.String()] = acc
+	}
+	acc.balance = acc.balance.Add(coins...)
+}
+
+// account returns a copy of the account of addr.
+func (c *mockChain) account(addr sdk.AccAddress) mockAccount {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	acc := c.accounts[addr.String()]
+	if acc == nil {
+		return mockAccount{}
+	}
+	return *acc
+}
+
+// setSequence overwrites the sequence of addr, as if another process had sent transactions.
+func (c *mockChain) setSequence(addr sdk.AccAddress, sequence uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.accounts[addr.String()].sequence = sequence
+}
+
+// setBaseFee sets the EIP-1559 base fee, nil if the base fee is disabled.
+func (c *mockChain) setBaseFee(baseFee *sdkmath.Int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.baseFee = baseFee
+}
+
+// setIndexing pauses or resumes indexing: while paused GetTx does not find included
+// transactions, which are indexed when indexing resumes.
+func (c *mockChain) setIndexing(on bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.noIndex = !on
+	if on {
+		for _, resp := range c.held {
+			c.txs[resp.TxHash] = resp
+		}
+		c.held = nil
+	}
+}
+
+// failExecution makes the messages of the next delivered transaction fail with err after
+// it passed the ante handler, so that it is included with the code of err.
+func (c *mockChain) failExecution(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.execFail = err
+}
+
+// includedTx returns the decoded transaction txHash, which the chain included.
+func (c *mockChain) includedTx(t *testing.T, txHash string) authSigning.Tx {
+	t.Helper()
+
+	c.mu.Lock()
+	txBytes, ok := c.txBytes[txHash]
+	c.mu.Unlock()
+	if !ok {
+		t.Fatalf("tx %v not included", txHash)
+	}
+
+	decoded, err := txConfig.TxDecoder()(txBytes)
+	if err != nil {
+		t.Fatalf("decode tx %v: %v", txHash, err)
+	}
+
+	return decoded.(authSigning.Tx)
+}
+
+// include indexes resp as included at the current height. Callers must hold c.mu.
+func (c *mockChain) include(resp sdk.TxResponse, txBytes []byte) {
+	c.txBytes[resp.TxHash] = txBytes
+	resp.Height = c.height
+	if c.noIndex {
+		c.held = append(c.held, &resp)
+		return
+	}
+	c.txs[resp.TxHash] = &resp
+}
+
+func (c *mockChain) latestHeight() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.height
+}
+
+// deliver runs txBytes through the checks of the ante handler and its messages, and commits
+// the result unless simulate is set. A MsgSend emits a transfer event.
+func (c *mockChain) deliver(txBytes []byte, simulate bool) (uint64, []abci.Event, error) {
+	decoded, err := txConfig.TxDecoder()(txBytes)
+	if err != nil {
+		return 0, nil, errorsmod.Wrap(sdkerrors.ErrTxDecode, err.Error())
+	}
+	tx, ok := decoded.(authSigning.Tx)
+	if !ok {
+		return 0, nil, errorsmod.Wrap(sdkerrors.ErrTxDecode, "not a signing tx")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	gasUsed := mockTxGas + mockMsgGas*uint64(len(tx.GetMsgs()))
+	if gasUsed > tx.GetGas() {
+		return tx.GetGas(), nil, errorsmod.Wrapf(sdkerrors.ErrOutOfGas, "out of gas in location: mock; gasWanted: %d, gasUsed: %d", tx.GetGas(), gasUsed)
+	}
+
+	sigs, err := tx.GetSignaturesV2()
+	if err != nil {
+		return gasUsed, nil, errorsmod.Wrap(sdkerrors.ErrTxDecode, err.Error())
+	}
+	signers := tx.GetSigners()
+	if len(sigs) != len(signers) {
+		return gasUsed, nil, errorsmod.Wrapf(sdkerrors.ErrUnauthorized, "wrong number of signers; expected %d, got %d", len(signers), len(sigs))
+	}
+	for i, signer := range signers {
+		acc := c.accounts[signer.String()]
+		if acc == nil {
+			return gasUsed, nil, errorsmod.Wrapf(sdkerrors.ErrUnknownAddress, "account %s does not exist", signer)
+		}
+		if sigs[i].Sequence != acc.sequence {
+			return gasUsed, nil, errorsmod.Wrapf(sdkerrors.ErrWrongSequence, "account sequence mismatch, expected %d, got %d", acc.sequence, sigs[i].Sequence)
+		}
+		if simulate {
+			continue
+		}
+
+		signerData := authSigning.SignerData{
+			ChainID:       testChainID,
+			AccountNumber: acc.number,
+			Sequence:      acc.sequence,
+			Address:       signer.String(),
+			PubKey:        sigs[i].PubKey,
+		}
+		if err := authSigning.VerifySignature(sigs[i].PubKey, signerData, sigs[i].Data, txConfig.SignModeHandler(), tx); err != nil {
+			return gasUsed, nil, errorsmod.Wrapf(sdkerrors.ErrUnauthorized, "signature verification failed; please verify account number (%d) and chain-id (%s)", acc.number, testChainID)
+		}
+	}
+
+	balances := make(map[string]sdk.Coins, len(c.accounts))
+	for addr, acc := range c.accounts {
+		balances[addr] = acc.balance
+	}
+	spend := func(addr string, coins sdk.Coins) error {
+		balance, negative := balances[addr].SafeSub(coins...)
+		if negative {
+			return errorsmod.Wrapf(sdkerrors.ErrInsufficientFunds, "%s is smaller than %s", balances[addr], coins)
+		}
+		balances[addr] = balance
+		return nil
+	}
+
+	commit := func() {
+		for _, signer := range signers {
+			c.accounts[signer.String()].sequence++
+		}
+		for addr, balance := range balances {
+			acc, ok := c.accounts[addr]
+			if !ok {
+				acc = &mockAccount{number: uint64(len(c.accounts))}
+				c.accounts[addr] = acc
+			}
+			acc.balance = balance
+		}
+		c.height++
+	}
+
+	if !simulate {
+		// a fee granter pays as if its allowance covered the fee
+		feeAccount := tx.FeePayer()
+		if granter := tx.FeeGranter(); !granter.Empty() {
+			feeAccount = granter
+		}
+		if err := spend(feeAccount.String(), tx.GetFee()); err != nil {
+			return gasUsed, nil, errorsmod.Wrapf(err, "insufficient funds to pay for fees")
+		}
+		if c.execFail != nil {
+			err := &mockExecError{err: c.execFail}
+			c.execFail = nil
+			commit()
+			return gasUsed, nil, err
+		}
+	}
+	var events []abci.Event
+	for _, msg := range tx.GetMsgs() {
+		send, ok := msg.(*bankTypes.MsgSend)
+		if !ok {
+			continue
+		}
+		if err := spend(send.FromAddress, send.Amount); err != nil {
+			return gasUsed, nil, err
+		}
+		balances[send.ToAddress] = balances[send.ToAddress].Add(send.Amount...)
+		events = append(events, abci.Event{Type: bankTypes.EventTypeTransfer, Attributes: []abci.EventAttribute{
+			{Key: []byte(bankTypes.AttributeKeyRecipient), Value: []byte(send.ToAddress)},
+			{Key: []byte(bankTypes.AttributeKeySender), Value: []byte(send.FromAddress)},
+			{Key: []byte(sdk.AttributeKeyAmount), Value: []byte(send.Amount.String())},
+		}})
+	}
+	if simulate {
+		return gasUsed, nil, nil
+	}
+
+	commit()
+	return gasUsed, events, nil
+}
+
+// mockNode is one gRPC endpoint of a mockChain, which can trail the chain or stop.
+type mockNode struct {
+	chain *mockChain
+	addr  string
+	srv   *grpc.Server
+
+	mu      sync.Mutex
+	lag     int64
+	syncing bool
+	calls   map[string]int
+}
+
+// serve starts a node of c on a local port; it is stopped when the test ends.
+func (c *mockChain) serve(t *testing.T) *mockNode {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	node := &mockNode{chain: c, addr: lis.Addr().String(), calls: make(map[string]int)}
+	node.srv = grpc.NewServer(grpc.ForceServerCodec(cdc.GRPCCodec()), grpc.UnaryInterceptor(node.count))
+	authTypes.RegisterQueryServer(node.srv, &mockAuthQuery{chain: c})
+	bankTypes.RegisterQueryServer(node.srv, &mockBankQuery{chain: c})
+	feemarketTypes.RegisterQueryServer(node.srv, &mockFeemarketQuery{chain: c})
+	sdkTx.RegisterServiceServer(node.srv, &mockTxService{chain: c})
+	tmservice.RegisterServiceServer(node.srv, &mockTmService{node: node})
+
+	go func() { _ = node.srv.Serve(lis) }()
+	t.Cleanup(node.srv.Stop)
+
+	return node
+}
+
+func (n *mockNode) count(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	n.mu.Lock()
+	n.calls[info.FullMethod]++
+	n.mu.Unlock()
+
+	return handler(ctx, req)
+}
+
+// callCount returns how many calls of the full gRPC method the node served.
+func (n *mockNode) callCount(method string) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.calls[method]
+}
+
+// setLag makes the node report a height of blocks behind the chain.
+func (n *mockNode) setLag(blocks int64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.lag = blocks
+}
+
+type mockAuthQuery struct {
+	authTypes.UnimplementedQueryServer
+	chain *mockChain
+}
+
+func (q *mockAuthQuery) Account(_ context.Context, req *authTypes.QueryAccountRequest) (*authTypes.QueryAccountResponse, error) {
+	addr, err := sdk.AccAddressFromBech32(req.Address)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	q.chain.mu.Lock()
+	acc := q.chain.accounts[addr.String()]
+	q.chain.mu.Unlock()
+	if acc == nil {
+		return nil, status.Errorf(codes.NotFound, "account %s not found", req.Address)
+	}
+
+	ethAccount := &cysicTypes.EthAccount{BaseAccount: authTypes.NewBaseAccount(addr, nil, acc.number, acc.sequence)}
+	anyAccount, err := codecTypes.NewAnyWithValue(ethAccount)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &authTypes.QueryAccountResponse{Account: anyAccount}, nil
+}
+
+type mockBankQuery struct {
+	bankTypes.UnimplementedQueryServer
+	chain *mockChain
+}
+
+func (q *mockBankQuery) AllBalances(_ context.Context, req *bankTypes.QueryAllBalancesRequest) (*bankTypes.QueryAllBalancesResponse, error) {
+	q.chain.mu.Lock()
+	defer q.chain.mu.Unlock()
+
+	var balances sdk.Coins
+	if acc := q.chain.accounts[req.Address]; acc != nil {
+		balances = acc.balance
+	}
+
+	return &bankTypes.QueryAllBalancesResponse{Balances: balances}, nil
+}
+
+type mockFeemarketQuery struct {
+	feemarketTypes.UnimplementedQueryServer
+	chain *mockChain
+}
+
+func (q *mockFeemarketQuery) BaseFee(_ context.Context, _ *feemarketTypes.QueryBaseFeeRequest) (*feemarketTypes.QueryBaseFeeResponse, error) {
+	q.chain.mu.Lock()
+	defer q.chain.mu.Unlock()
+
+	return &feemarketTypes.QueryBaseFeeResponse{BaseFee: q.chain.baseFee}, nil
+}
+
+type mockTxService struct {
+	sdkTx.UnimplementedServiceServer
+	chain *mockChain
+}
+
+// Simulate fails like the SDK's tx service behind the ABCI query router: with an Unknown
+// status nesting the log, where the error was created and the gas info.
+func (s *mockTxService) Simulate(_ context.Context, req *sdkTx.SimulateRequest) (*sdkTx.SimulateResponse, error) {
+	gasUsed, _, err := s.chain.deliver(req.TxBytes, true)
+	if err != nil {
+		return nil, status.Errorf(codes.Unknown, "rpc error: code = Unknown desc = %v With gas wanted: '%d' and gas used: '%d' : unknown request", err, gasLimit, gasUsed)
+	}
+
+	return &sdkTx.SimulateResponse{GasInfo: &sdk.GasInfo{GasUsed: gasUsed}}, nil
+}
+
+func (s *mockTxService) BroadcastTx(_ context.Context, req *sdkTx.BroadcastTxRequest) (*sdkTx.BroadcastTxResponse, error) {
+	resp := &sdk.TxResponse{TxHash: fmt.Sprintf("%X", tmhash.Sum(req.TxBytes))}
+	gasUsed, events, err := s.chain.deliver(req.TxBytes, false)
+	resp.GasUsed = int64(gasUsed)
+
+	var execErr *mockExecError
+	switch {
+	case errors.As(err, &execErr):
+		// CheckTx passed, the messages fail in the block
+		included := *resp
+		included.Codespace, included.Code, included.RawLog = errorsmod.ABCIInfo(execErr.err, false)
+		s.chain.mu.Lock()
+		s.chain.include(included, req.TxBytes)
+		s.chain.mu.Unlock()
+	case err != nil:
+		resp.Codespace, resp.Code, resp.RawLog = errorsmod.ABCIInfo(err, false)
+	default:
+		included := *resp
+		included.Events = events
+		s.chain.mu.Lock()
+		s.chain.include(included, req.TxBytes)
+		s.chain.mu.Unlock()
+	}
+
+	if req.Mode == sdkTx.BroadcastMode_BROADCAST_MODE_ASYNC {
+		// the node answers before CheckTx
+		return &sdkTx.BroadcastTxResponse{TxResponse: &sdk.TxResponse{TxHash: resp.TxHash}}, nil
+	}
+	return &sdkTx.BroadcastTxResponse{TxResponse: resp}, nil
+}
+
+// GetTx finds the transactions the chain included, failing like the SDK's tx service for others.
+func (s *mockTxService) GetTx(_ context.Context, req *sdkTx.GetTxRequest) (*sdkTx.GetTxResponse, error) {
+	s.chain.mu.Lock()
+	defer s.chain.mu.Unlock()
+
+	resp, ok := s.chain.txs[strings.ToUpper(req.Hash)]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "tx not found: %s", req.Hash)
+	}
+
+	return &sdkTx.GetTxResponse{TxResponse: resp}, nil
+}
+
+type mockTmService struct {
+	tmservice.UnimplementedServiceServer
+	node *mockNode
+}
+
+func (s *mockTmService) GetSyncing(context.Context, *tmservice.GetSyncingRequest) (*tmservice.GetSyncingResponse, error) {
+	s.node.mu.Lock()
+	defer s.node.mu.Unlock()
+
+	return &tmservice.GetSyncingResponse{Syncing: s.node.syncing}, nil
+}
+
+func (s *mockTmService) GetLatestBlock(context.Context, *tmservice.GetLatestBlockRequest) (*tmservice.GetLatestBlockResponse, error) {
+	height := s.node.chain.latestHeight()
+
+	s.node.mu.Lock()
+	defer s.node.mu.Unlock()
+
+	return &tmservice.GetLatestBlockResponse{Block: &tmproto.Block{Header: tmproto.Header{ChainID: testChainID, Height: height - s.node.lag}}}, nil
+}
+
+// newTestSigner returns a Signer with a new eth_secp256k1 key.
+func newTestSigner(t *testing.T) *Signer {
+	t.Helper()
+
+	privKey, err := ethsecp256k1.GenerateKey()
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	signer, err := NewSignerWithPrivateKeyAndAlgo(privKey.Bytes(), ethsecp256k1.KeyType)
+	if err != nil {
+		t.Fatalf("new signer: %v", err)
+	}
+
+	return signer
+}
+
+// testCoins returns amount base units of the gas coin.
+func testCoins(amount int64) sdk.Coins {
+	return sdk.NewCoins(sdk.NewCoin(CYSToken, sdkmath.NewInt(amount)))
+}
+
+// fastRetry retries like DefaultRetryPolicy without making tests wait.
+func fastRetry() RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	policy.MaxBackoff = 10 * time.Millisecond
+	return policy
+}
+
+// newTestServer creates a Server on the endpoint of node, closed when the test ends.
+func newTestServer(t *testing.T, node *mockNode, opts ...Option) *Server {
+	t.Helper()
+
+	s, err := NewServer(node.addr, testChainID, append([]Option{WithRetryPolicy(fastRetry())}, opts...)...)
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+
+	return s
+}
+
+func TestServerConcurrentUse(t *testing.T) {
+	chain := newMockChain()
+	node := chain.serve(t)
+	s := newTestServer(t, node, WithGas(CYSToken, 1), WithLocalSequences())
+
+	shared := newTestSigner(t)
+	chain.fund(shared.Address(), testCoins(1_000_000_000))
+	others := make([]*Signer, 4)
+	for i := range others {
+		others[i] = newTestSigner(t)
+		chain.fund(others[i].Address(), testCoins(1_000_000_000))
+	}
+	recipient := newTestSigner(t).Address().String()
+
+	const sendsPerSigner = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, 256)
+	send := func(signer *Signer) {
+		defer wg.Done()
+		if _, err := s.Send(signer, recipient, CYSToken, sdkmath.NewInt(1)); err != nil {
+			errs <- fmt.Errorf("send from %v: %w", signer.Address(), err)
+		}
+	}
+
+	for i := 0; i < sendsPerSigner; i++ {
+		// transactions of the shared signer are serialized by the SequenceManager
+		wg.Add(1)
+		go send(shared)
+		for _, signer := range others {
+			wg.Add(1)
+			go send(signer)
+		}
+
+		wg.Add(4)
+		go func() {
+			defer wg.Done()
+			if _, err := s.GetBalanceList(shared.Address().String()); err != nil {
+				errs <- fmt.Errorf("get balances: %w", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := s.GetAccount(shared); err != nil {
+				errs <- fmt.Errorf("get account: %w", err)
+			}
+		}()
+		go func(price int64) {
+			defer wg.Done()
+			if err := s.SetGas(CYSToken, price); err != nil {
+				errs <- fmt.Errorf("set gas: %w", err)
+			}
+			_ = s.GasPrice()
+		}(int64(i%3 + 1))
+		go func(limit uint64) {
+			defer wg.Done()
+			if err := s.SetGasLimit(limit); err != nil {
+				errs <- fmt.Errorf("set gas limit: %w", err)
+			}
+			_ = s.GasLimit()
+		}(gasLimit - uint64(i)*1_000)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+
+	if got := chain.account(shared.Address()).sequence; got != sendsPerSigner {
+		t.Errorf("shared signer sequence = %d, want %d", got, sendsPerSigner)
+	}
+	for _, signer := range others {
+		if got := chain.account(signer.Address()).sequence; got != sendsPerSigner {
+			t.Errorf("signer %v sequence = %d, want %d", signer.Address(), got, sendsPerSigner)
+		}
+	}
+
+	// reading the account shows the chain committed every sequence
+	if _, err := s.GetAccount(shared); err != nil {
+		t.Fatalf("get account: %v", err)
+	}
+	if pending := s.SequenceManager().Pending(shared.Address().String()); len(pending) != 0 {
+		t.Errorf("pending txs of shared signer = %+v, want none", pending)
+	}
+}
+
+func TestSequenceManagerPrunesCommittedTxs(t *testing.T) {
+	chain := newMockChain()
+	node := chain.serve(t)
+	s := newTestServer(t, node, WithGas(CYSToken, 1), WithLocalSequences())
+
+	signer := newTestSigner(t)
+	chain.fund(signer.Address(), testCoins(1_000_000))
+	recipient := newTestSigner(t).Address().String()
+
+	hashes := make([]string, 3)
+	for i := range hashes {
+		hash, err := s.Send(signer, recipient, CYSToken, sdkmath.NewInt(1))
+		if err != nil {
+			t.Fatalf("send %d: %v", i, err)
+		}
+		hashes[i] = hash
+	}
+	pending := s.SequenceManager().Pending(signer.Address().String())
+	if len(pending) != len(hashes) {
+		t.Fatalf("pending txs = %+v, want %d", pending, len(hashes))
+	}
+	for i, tx := range pending {
+		if tx.Sequence != uint64(i) || tx.TxHash != hashes[i] {
+			t.Errorf("pending tx %d = %+v, want sequence %d with hash %v", i, tx, i, hashes[i])
+		}
+	}
+
+	// the inclusion of a tx commits the sequences up to its own
+	if _, err := s.AwaitTx(strings.ToLower(hashes[1])); err != nil {
+		t.Fatalf("await tx: %v", err)
+	}
+	pending = s.SequenceManager().Pending(signer.Address().String())
+	if len(pending) != 1 || pending[0].TxHash != hashes[2] {
+		t.Errorf("pending txs after awaiting the second = %+v, want the third only", pending)
+	}
+
+	if _, err := s.GetAccount(signer); err != nil {
+		t.Fatalf("get account: %v", err)
+	}
+	if pending := s.SequenceManager().Pending(signer.Address().String()); len(pending) != 0 {
+		t.Errorf("pending txs after reading the account = %+v, want none", pending)
+	}
+}
+
+func TestServerConcurrentEstimateAndBroadcast(t *testing.T) {
+	chain := newMockChain()
+	node := chain.serve(t)
+	s := newTestServer(t, node, WithLocalSequences())
+
+	signer := newTestSigner(t)
+	chain.fund(signer.Address(), testCoins(1_000))
+	msg, err := NewSendMsg(signer.Address().String(), newTestSigner(t).Address().String(), CYSToken, sdkmath.NewInt(1))
+	if err != nil {
+		t.Fatalf("new send msg: %v", err)
+	}
+
+	const n = 10
+	var wg sync.WaitGroup
+	results := make(chan *BroadcastResult, n)
+	for i := 0; i < n; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			estimate, err := s.EstimateGas(signer, []sdk.Msg{msg})
+			if err != nil {
+				t.Errorf("estimate gas: %v", err)
+				return
+			}
+			if estimate.GasUsed != mockTxGas+mockMsgGas {
+				t.Errorf("gas used = %d, want %d", estimate.GasUsed, mockTxGas+mockMsgGas)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			result, err := s.BroadcastMsgs(signer, []sdk.Msg{msg})
+			if err != nil {
+				t.Errorf("broadcast: %v", err)
+				return
+			}
+			results <- result
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	seen := make(map[uint64]bool)
+	for result := range results {
+		if seen[result.Sequence] {
+			t.Errorf("sequence %d used twice", result.Sequence)
+		}
+		seen[result.Sequence] = true
+	}
+	if len(seen) != n {
+		t.Errorf("broadcast %d txs, want %d", len(seen), n)
+	}
+}
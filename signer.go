@@ -1,31 +1,137 @@
 package gosdk
 
 import (
-	"log"
+	"context"
+	"errors"
+	"fmt"
 
 	"github.com/cysic-tech/gosdk/crypto/ethsecp256k1"
 	etherminthd "github.com/cysic-tech/gosdk/crypto/hd"
 
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
 	"github.com/cosmos/cosmos-sdk/crypto/types"
-	"github.com/cosmos/cosmos-sdk/simapp"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 )
 
+// TxSigner is an account that signs transactions. Every Server write method accepts one, so
+// keys can live outside the process, e.g. in a KMS or behind a RemoteSigner; Signer is the
+// in-memory implementation.
+type TxSigner interface {
+	// Address returns the account address.
+	Address() sdk.AccAddress
+	// PubKey returns the public key of the account.
+	PubKey() types.PubKey
+	// SignBytes signs msg as PrivKey.Sign of the key type does: an eth_secp256k1 key signs
+	// the Keccak-256 hash of msg, or msg itself if it is a 32 byte digest.
+	SignBytes(ctx context.Context, msg []byte) ([]byte, error)
+	// SignModes returns the sign modes the signer supports.
+	SignModes() []TxSignMode
+}
+
+// ErrNotEthKey is returned by operations that only make sense for Ethereum (eth_secp256k1) keys,
+// such as EIP-191 and EIP-712 signing or keystore export, when the key is a Cosmos secp256k1 key.
+var ErrNotEthKey = fmt.Errorf("operation needs an %v key", ethsecp256k1.KeyType)
+
+// ErrInvalidPrivateKey is returned when private key bytes are not a valid secp256k1 key: 32
+// bytes encoding a non-zero scalar below the curve order.
+var ErrInvalidPrivateKey = errors.New("invalid private key")
+
+// Signer holds a private key and the addresses derived from it.
+//
+// The key is an eth_secp256k1 key or a Cosmos secp256k1 key. The address of a secp256k1 key is
+// derived as on Cosmos chains; EthAddr is then the hex form of that address, which holds the
+// account's EVM balance but can't sign Ethereum transactions or messages.
+//
+// Server methods take a Signer by value and never modify it, so one Signer can be shared
+// by many goroutines as long as callers do not mutate its fields; use WithNonce to get a
+// copy with a different sequence instead of assigning Nonce on a shared Signer.
 type Signer struct {
 	CosmosAddr sdk.AccAddress
 	EthAddr    common.Address
 	privateKey types.PrivKey
 	publicKey  types.PubKey
-	Nonce      uint64
+	// Nonce, when greater than the on-chain sequence, is used as the sequence of the next tx.
+	Nonce uint64
+}
+
+// WithNonce returns a copy of the Signer that signs with the given sequence.
+//
+// @param nonce the sequence to use for the next transaction
+// @return the copied Signer
+func (s Signer) WithNonce(nonce uint64) Signer {
+	s.Nonce = nonce
+	return s
+}
+
+// Address returns the Cosmos address of the Signer.
+func (s Signer) Address() sdk.AccAddress {
+	return s.CosmosAddr
+}
+
+// PubKey returns the public key of the Signer.
+func (s Signer) PubKey() types.PubKey {
+	return s.publicKey
+}
+
+// SignBytes signs msg with the private key of the Signer.
+//
+// @param ctx the context, unused as signing is local
+// @param msg the bytes to sign
+// @return the signature, or an error if the Signer has no key
+func (s Signer) SignBytes(_ context.Context, msg []byte) ([]byte, error) {
+	if s.privateKey == nil {
+		return nil, fmt.Errorf("signer %v has no private key", s.CosmosAddr.String())
+	}
+
+	return s.privateKey.Sign(msg)
+}
+
+// SignModes returns the sign modes of the key of the Signer: EIP-712 needs an eth_secp256k1 key.
+func (s Signer) SignModes() []TxSignMode {
+	if _, ok := s.privateKey.(*ethsecp256k1.PrivKey); ok {
+		return []TxSignMode{SignModeDirect, SignModeEIP712}
+	}
+
+	return []TxSignMode{SignModeDirect}
+}
+
+// signerNonce returns the Nonce of an in-memory Signer, zero for other signers.
+func signerNonce(signer TxSigner) uint64 {
+	switch s := signer.(type) {
+	case Signer:
+		return s.Nonce
+	case *Signer:
+		return s.Nonce
+	}
+
+	return 0
+}
+
+// supportsSignMode reports whether signer can sign in mode.
+func supportsSignMode(signer TxSigner, mode TxSignMode) bool {
+	for _, supported := range signer.SignModes() {
+		if supported == mode {
+			return true
+		}
+	}
+
+	return false
 }
 
-// NewSignerWithPrivateKey creates a new Signer instance from a private key.
+// NewSignerWithPrivateKey creates a new Signer instance from an eth_secp256k1 private key. The
+// bytes are always taken as an Ethereum key; use NewSignerWithPrivateKeyAndAlgo for a Cosmos
+// secp256k1 key, whose address differs.
 //
-// @param bz the private key bytes
+// The key is not validated: shorter bytes are zero-padded and an all-zero key panics.
+//
+// Deprecated: use NewSignerWithPrivateKeyAndAlgo(bz, "eth_secp256k1"), which returns
+// ErrInvalidPrivateKey for such keys.
+//
+// @param bz the eth_secp256k1 private key bytes
 // @return a new Signer instance
 func NewSignerWithPrivateKey(bz []byte) *Signer {
 	bzArr := make([]byte, ethsecp256k1.PrivKeySize)
@@ -33,50 +139,71 @@ func NewSignerWithPrivateKey(bz []byte) *Signer {
 	privKey := &ethsecp256k1.PrivKey{
 		Key: bzArr,
 	}
-	cosmosAddr := sdk.AccAddress(privKey.PubKey().Address())
-	ethAddr := common.BytesToAddress(cosmosAddr)
 
-	return &Signer{
-		CosmosAddr: cosmosAddr,
-		EthAddr:    ethAddr,
-		privateKey: privKey,
-		publicKey:  privKey.PubKey(),
+	return newSignerWithPrivKey(privKey)
+}
+
+// NewSignerWithPrivateKeyAndAlgo creates a new Signer instance from a private key of the given algorithm.
+//
+// @param bz the 32 private key bytes
+// @param algo the signing algorithm of the key, "eth_secp256k1" or "secp256k1"
+// @return a new Signer instance, or an error if the algorithm is not supported or the key is invalid
+func NewSignerWithPrivateKeyAndAlgo(bz []byte, algo string) (*Signer, error) {
+	signAlgo, err := signingAlgo(algo)
+	if err != nil {
+		logger().Error("error when new signing algo from string", "err", err)
+		return nil, err
+	}
+	// both algorithms use the secp256k1 curve
+	if _, err := crypto.ToECDSA(bz); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPrivateKey, err)
 	}
+
+	return newSignerWithPrivKey(signAlgo.Generate()(bz)), nil
 }
 
 // NewSignerWithMnemonic creates a new Signer instance from a mnemonic and HD path.
 //
 // @param mnemonic the mnemonic phrase
 // @param passphrase the passphrase for the mnemonic
-// @param hdPath the HD path to derive the key
-// @param algo the signing algorithm to use
+// @param hdPath the HD path to derive the key, e.g. BIP44HDPath, or CosmosHDPath for secp256k1 keys
+// @param algo the signing algorithm to use, "eth_secp256k1" or "secp256k1"
 // @return a new Signer instance, or an error if derivation fails
 func NewSignerWithMnemonic(mnemonic string, passphrase string, hdPath string, algo string) (*Signer, error) {
-	kb := keyring.NewInMemory(simapp.MakeTestEncodingConfig().Codec, etherminthd.EthSecp256k1Option())
-	keyringAlgoList, _ := kb.SupportedAlgorithms()
-	signAlgo, err := keyring.NewSigningAlgoFromString(algo, keyringAlgoList)
+	signAlgo, err := signingAlgo(algo)
 	if err != nil {
-		log.Printf("error when new signing algo from string, err: %v", err.Error())
+		logger().Error("error when new signing algo from string", "err", err)
 		return nil, err
 	}
 
 	derivedPriv, err := signAlgo.Derive()(mnemonic, passphrase, hdPath)
 	if err != nil {
-		log.Printf("error when get derive private key, err: %v\n", err.Error())
+		logger().Error("error when get derive private key", "err", err)
 		return nil, err
 	}
-	privKey := signAlgo.Generate()(derivedPriv)
 
+	return newSignerWithPrivKey(signAlgo.Generate()(derivedPriv)), nil
+}
+
+// signingAlgo returns the key derivation algorithm named algo, e.g. "eth_secp256k1".
+func signingAlgo(algo string) (keyring.SignatureAlgo, error) {
+	options := keyring.Options{}
+	etherminthd.EthSecp256k1Option()(&options)
+
+	return keyring.NewSigningAlgoFromString(algo, options.SupportedAlgos)
+}
+
+// newSignerWithPrivKey creates a Signer holding privKey.
+func newSignerWithPrivKey(privKey types.PrivKey) *Signer {
 	cosmosAddr := sdk.AccAddress(privKey.PubKey().Address())
 	ethAddr := common.BytesToAddress(cosmosAddr)
 
-	// log.Printf("addr: %v, privKey: %v", ethAddr, hex.EncodeToString(privKey.Bytes()))
 	return &Signer{
 		CosmosAddr: cosmosAddr,
 		EthAddr:    ethAddr,
 		privateKey: privKey,
 		publicKey:  privKey.PubKey(),
-	}, nil
+	}
 }
 
 // VerifyEthPersonalSignature verifies an Ethereum personal signature.
@@ -94,7 +221,7 @@ func VerifyEthPersonalSignature(address string, data []byte, sig []byte) bool {
 
 	sigPublicKey, err := crypto.SigToPub(sigHash, sig)
 	if err != nil {
-		log.Println("invalid signature crypto.SigToPub err: ", err)
+		logger().Error("invalid signature", "err", err)
 
 		return false
 	}
@@ -107,14 +234,17 @@ func VerifyEthPersonalSignature(address string, data []byte, sig []byte) bool {
 //
 // @param s the Signer instance
 // @param data the data to sign
-// @return the signature, or an error if signing fails
+// @return the signature, or ErrNotEthKey if the Signer has no Ethereum key
 func (s *Signer) EthPersonalSign(data []byte) ([]byte, error) {
 	waitSignHash, _ := accounts.TextAndHash(data)
 
-	priv := s.privateKey.(*ethsecp256k1.PrivKey)
+	priv, ok := s.privateKey.(*ethsecp256k1.PrivKey)
+	if !ok {
+		return nil, ErrNotEthKey
+	}
 	privKey, err := crypto.ToECDSA(priv.Key)
 	if err != nil {
-		log.Printf("error when convert private key, err: %v", err.Error())
+		logger().Error("error when convert private key", "err", err)
 		return nil, err
 	}
 
@@ -130,3 +260,87 @@ func (s *Signer) EthPersonalSign(data []byte) ([]byte, error) {
 func (s *Signer) VerifyEthPersonalSignature(data []byte, sig []byte) bool {
 	return VerifyEthPersonalSignature(s.EthAddr.String(), data, sig)
 }
+
+// TypedDataHash returns the EIP-712 digest of typedData: keccak256("\x19\x01" || domainSeparator || hashStruct(message)).
+//
+// @param typedData the typed data, with its EIP712Domain type and domain
+// @return the 32 byte digest, or an error if the types or the message are inconsistent
+func TypedDataHash(typedData apitypes.TypedData) ([]byte, error) {
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		logger().Error("error when hash typed data", "err", err)
+		return nil, err
+	}
+
+	return hash, nil
+}
+
+// SignTypedData signs EIP-712 typed data as eth_signTypedData_v4 does.
+//
+// @param s the Signer instance
+// @param typedData the typed data to sign
+// @return the 65 byte signature with V 27 or 28, ErrNotEthKey if the Signer has no Ethereum key,
+// or an error if hashing fails
+func (s *Signer) SignTypedData(typedData apitypes.TypedData) ([]byte, error) {
+	if _, ok := s.privateKey.(*ethsecp256k1.PrivKey); !ok {
+		return nil, ErrNotEthKey
+	}
+
+	hash, err := TypedDataHash(typedData)
+	if err != nil {
+		return nil, err
+	}
+
+	return signEIP712Hash(context.Background(), s, hash)
+}
+
+// RecoverTypedDataSigner recovers the address that signed EIP-712 typed data.
+//
+// @param typedData the typed data that was signed
+// @param sig the 65 byte signature, with V 0/1 or 27/28
+// @return the Ethereum address of the signer, or an error if the signature is malformed
+func RecoverTypedDataSigner(typedData apitypes.TypedData, sig []byte) (common.Address, error) {
+	if len(sig) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("invalid signature length %d, expected %d", len(sig), crypto.SignatureLength)
+	}
+
+	hash, err := TypedDataHash(typedData)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	sigCopy := make([]byte, len(sig))
+	copy(sigCopy, sig)
+	if sigCopy[crypto.RecoveryIDOffset] >= 27 {
+		sigCopy[crypto.RecoveryIDOffset] -= 27
+	}
+
+	pubKey, err := crypto.SigToPub(hash, sigCopy)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return crypto.PubkeyToAddress(*pubKey), nil
+}
+
+// VerifyTypedDataSignature verifies an EIP-712 typed data signature.
+//
+// @param address the hex or bech32 address that signed the typed data
+// @param typedData the typed data that was signed
+// @param sig the signature to verify
+// @return true if the signature is valid, false otherwise
+func VerifyTypedDataSignature(address string, typedData apitypes.TypedData, sig []byte) bool {
+	accAddr, err := toAccAddress(address)
+	if err != nil {
+		logger().Error("error when convert addr", "address", address, "err", err)
+		return false
+	}
+
+	recovered, err := RecoverTypedDataSigner(typedData, sig)
+	if err != nil {
+		logger().Error("error when recover typed data signer", "err", err)
+		return false
+	}
+
+	return recovered == common.BytesToAddress(accAddr)
+}
@@ -0,0 +1,196 @@
+package gosdk
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+func TestCosmosKeyNotEthKey(t *testing.T) {
+	privKey := secp256k1.GenPrivKey()
+	signer, err := NewSignerWithPrivateKeyAndAlgo(privKey.Bytes(), "secp256k1")
+	if err != nil {
+		t.Fatalf("new signer: %v", err)
+	}
+	if want := sdk.AccAddress(privKey.PubKey().Address()); !signer.Address().Equals(want) {
+		t.Errorf("address = %v, want the Cosmos address %v", signer.Address(), want)
+	}
+	// the same bytes taken as an Ethereum key belong to another account
+	eth, err := NewSignerWithPrivateKeyAndAlgo(privKey.Bytes(), "eth_secp256k1")
+	if err != nil {
+		t.Fatalf("new eth signer: %v", err)
+	}
+	if eth.Address().Equals(signer.Address()) {
+		t.Errorf("eth_secp256k1 signer has the Cosmos address %v", eth.Address())
+	}
+
+	if _, err := signer.EthPersonalSign([]byte("hello")); !errors.Is(err, ErrNotEthKey) {
+		t.Errorf("EthPersonalSign: got %v, want ErrNotEthKey", err)
+	}
+	if _, err := signer.ExportKeystore("secret", LightScryptParams); !errors.Is(err, ErrNotEthKey) {
+		t.Errorf("ExportKeystore: got %v, want ErrNotEthKey", err)
+	}
+	typedData := apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {{Name: "name", Type: "string"}},
+			"Mail":         {{Name: "contents", Type: "string"}},
+		},
+		PrimaryType: "Mail",
+		Domain:      apitypes.TypedDataDomain{Name: "gosdk"},
+		Message:     apitypes.TypedDataMessage{"contents": "hello"},
+	}
+	if _, err := signer.SignTypedData(typedData); !errors.Is(err, ErrNotEthKey) {
+		t.Errorf("SignTypedData: got %v, want ErrNotEthKey", err)
+	}
+
+	// an eth_secp256k1 key signs the same typed data
+	if _, err := newTestSigner(t).SignTypedData(typedData); err != nil {
+		t.Errorf("SignTypedData with an eth_secp256k1 key: %v", err)
+	}
+}
+
+func TestNewSignerWithPrivateKeyAndAlgoInvalidKey(t *testing.T) {
+	// the order of the secp256k1 curve, one past the largest valid key
+	order, err := hex.DecodeString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141")
+	if err != nil {
+		t.Fatalf("decode curve order: %v", err)
+	}
+
+	for name, bz := range map[string][]byte{
+		"empty":       nil,
+		"short":       make([]byte, 31),
+		"long":        append(make([]byte, 32), 1),
+		"zero":        make([]byte, 32),
+		"curve order": order,
+	} {
+		for _, algo := range []string{"eth_secp256k1", "secp256k1"} {
+			if _, err := NewSignerWithPrivateKeyAndAlgo(bz, algo); !errors.Is(err, ErrInvalidPrivateKey) {
+				t.Errorf("%v %v key: got %v, want ErrInvalidPrivateKey", name, algo, err)
+			}
+		}
+	}
+
+	if _, err := ImportPrivateKeyToKeyring(keyring.BackendMemory, "", "zero", "", make([]byte, 32)); !errors.Is(err, ErrInvalidPrivateKey) {
+		t.Errorf("import zero key: got %v, want ErrInvalidPrivateKey", err)
+	}
+}
+
+// mailTypedData is the example of EIP-712, signed by the key keccak256("cow").
+func mailTypedData() apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"Person": {
+				{Name: "name", Type: "string"},
+				{Name: "wallet", Type: "address"},
+			},
+			"Mail": {
+				{Name: "from", Type: "Person"},
+				{Name: "to", Type: "Person"},
+				{Name: "contents", Type: "string"},
+			},
+		},
+		PrimaryType: "Mail",
+		Domain: apitypes.TypedDataDomain{
+			Name:              "Ether Mail",
+			Version:           "1",
+			ChainId:           math.NewHexOrDecimal256(1),
+			VerifyingContract: "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC",
+		},
+		Message: apitypes.TypedDataMessage{
+			"from":     map[string]interface{}{"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+			"to":       map[string]interface{}{"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+			"contents": "Hello, Bob!",
+		},
+	}
+}
+
+func TestSignTypedDataMailVector(t *testing.T) {
+	const (
+		wantDigest = "be609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2"
+		// r || s || v of the example
+		wantSig   = "4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b915621c"
+		cowSigner = "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"
+	)
+
+	digest, err := TypedDataHash(mailTypedData())
+	if err != nil {
+		t.Fatalf("typed data hash: %v", err)
+	}
+	if got := hex.EncodeToString(digest); got != wantDigest {
+		t.Errorf("digest = %v, want %v", got, wantDigest)
+	}
+
+	signer := newOfflineSigner(t, hex.EncodeToString(crypto.Keccak256([]byte("cow"))))
+	if signer.EthAddr != common.HexToAddress(cowSigner) {
+		t.Fatalf("address = %v, want %v", signer.EthAddr, cowSigner)
+	}
+	sig, err := signer.SignTypedData(mailTypedData())
+	if err != nil {
+		t.Fatalf("sign typed data: %v", err)
+	}
+	if got := hex.EncodeToString(sig); got != wantSig {
+		t.Errorf("signature = %v, want %v", got, wantSig)
+	}
+}
+
+func TestTypedDataRoundTrip(t *testing.T) {
+	signer := newTestSigner(t)
+	typedData := mailTypedData()
+
+	sig, err := signer.SignTypedData(typedData)
+	if err != nil {
+		t.Fatalf("sign typed data: %v", err)
+	}
+	if v := sig[crypto.RecoveryIDOffset]; v != 27 && v != 28 {
+		t.Errorf("V = %d, want 27 or 28", v)
+	}
+
+	// wallets return V as 27/28, go-ethereum as 0/1
+	raw := append([]byte{}, sig...)
+	raw[crypto.RecoveryIDOffset] -= 27
+	for name, sig := range map[string][]byte{"V 27/28": sig, "V 0/1": raw} {
+		recovered, err := RecoverTypedDataSigner(typedData, sig)
+		if err != nil {
+			t.Fatalf("%v: recover: %v", name, err)
+		}
+		if recovered != signer.EthAddr {
+			t.Errorf("%v: recovered %v, want %v", name, recovered, signer.EthAddr)
+		}
+		if !VerifyTypedDataSignature(signer.EthAddr.Hex(), typedData, sig) || !VerifyTypedDataSignature(signer.Address().String(), typedData, sig) {
+			t.Errorf("%v: signature does not verify for the hex and bech32 address", name)
+		}
+	}
+	if raw[crypto.RecoveryIDOffset] >= 27 || sig[crypto.RecoveryIDOffset] < 27 {
+		t.Error("recovering modified the signature")
+	}
+
+	if VerifyTypedDataSignature(newTestSigner(t).EthAddr.Hex(), typedData, sig) {
+		t.Error("signature verifies for another address")
+	}
+	typedData.Message["contents"] = "Hello, Alice!"
+	if VerifyTypedDataSignature(signer.EthAddr.Hex(), typedData, sig) {
+		t.Error("signature verifies for other contents")
+	}
+
+	if _, err := RecoverTypedDataSigner(typedData, sig[:64]); err == nil || !strings.Contains(err.Error(), "invalid signature length 64") {
+		t.Errorf("short signature: got %v, want a length error", err)
+	}
+	if VerifyTypedDataSignature(signer.EthAddr.Hex(), typedData, sig[:64]) {
+		t.Error("short signature verifies")
+	}
+}
@@ -0,0 +1,228 @@
+package gosdk
+
+import (
+	"context"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	otelCodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// instrumentationName is the name of the tracer the SDK creates its spans with.
+const instrumentationName = "github.com/cysic-tech/gosdk"
+
+// Stages of the transaction pipeline, each traced as a child span of the BroadcastMsgs span.
+const (
+	stageAccountLookup = "account_lookup"
+	stageFeeQuote      = "fee_quote"
+	stageGasEstimate   = "gas_estimate"
+	stageSign          = "sign"
+	stageBroadcast     = "broadcast"
+)
+
+// Outcomes of a broadcast, as counted by Metrics.
+const (
+	broadcastAccepted = "accepted"
+	broadcastRejected = "rejected"
+	broadcastError    = "error"
+)
+
+// Metrics is a Prometheus collector of the gRPC calls and transactions of the Servers it is
+// passed to with WithMetrics. It exposes:
+//
+//   - <namespace>_rpc_duration_seconds: latency histogram of gRPC calls, by method and status code
+//   - <namespace>_rpc_errors_total: failed gRPC calls, by method and status code
+//   - <namespace>_tx_stage_duration_seconds: latency histogram of the transaction pipeline, by stage
+//   - <namespace>_tx_broadcasts_total: broadcasts, by outcome (accepted, rejected, error), codespace and code
+//   - <namespace>_tx_fees_total: fees of accepted transactions built by the Server, in base units, by denom
+//
+// Register it once, e.g. with prometheus.MustRegister; one Metrics can be shared by several Servers.
+type Metrics struct {
+	rpcDuration   *prometheus.HistogramVec
+	rpcErrors     *prometheus.CounterVec
+	stageDuration *prometheus.HistogramVec
+	broadcasts    *prometheus.CounterVec
+	fees          *prometheus.CounterVec
+}
+
+// NewMetrics creates the Prometheus collector of the SDK.
+//
+// @param namespace the prefix of the metric names, e.g. "cysic"; may be empty
+// @return a new Metrics instance, to be registered and passed to WithMetrics
+func NewMetrics(namespace string) *Metrics {
+	return &Metrics{
+		rpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "rpc_duration_seconds",
+			Help:      "Latency of gRPC calls to the chain.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "code"}),
+		rpcErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rpc_errors_total",
+			Help:      "Number of failed gRPC calls to the chain.",
+		}, []string{"method", "code"}),
+		stageDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "tx_stage_duration_seconds",
+			Help:      "Latency of the stages of the transaction pipeline.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"stage"}),
+		broadcasts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "tx_broadcasts_total",
+			Help:      "Number of transaction broadcasts by outcome.",
+		}, []string{"outcome", "codespace", "code"}),
+		fees: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "tx_fees_total",
+			Help:      "Fees of accepted transactions, in base units.",
+		}, []string{"denom"}),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.rpcDuration.Describe(ch)
+	m.rpcErrors.Describe(ch)
+	m.stageDuration.Describe(ch)
+	m.broadcasts.Describe(ch)
+	m.fees.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.rpcDuration.Collect(ch)
+	m.rpcErrors.Collect(ch)
+	m.stageDuration.Collect(ch)
+	m.broadcasts.Collect(ch)
+	m.fees.Collect(ch)
+}
+
+// observeRPC records a gRPC call. It is a no-op on a nil Metrics, as are the other observe methods.
+func (m *Metrics) observeRPC(method string, err error, latency time.Duration) {
+	if m == nil {
+		return
+	}
+
+	code := status.Code(err).String()
+	m.rpcDuration.WithLabelValues(method, code).Observe(latency.Seconds())
+	if err != nil {
+		m.rpcErrors.WithLabelValues(method, code).Inc()
+	}
+}
+
+// observeStage records a stage of the transaction pipeline.
+func (m *Metrics) observeStage(stage string, latency time.Duration) {
+	if m == nil {
+		return
+	}
+
+	m.stageDuration.WithLabelValues(stage).Observe(latency.Seconds())
+}
+
+// observeBroadcast records the outcome of a broadcast, and the fee of accepted transactions.
+func (m *Metrics) observeBroadcast(resp *sdk.TxResponse, err error, fee sdk.Coins) {
+	if m == nil {
+		return
+	}
+
+	switch {
+	case err != nil:
+		m.broadcasts.WithLabelValues(broadcastError, "", "").Inc()
+	case resp.Code != 0:
+		m.broadcasts.WithLabelValues(broadcastRejected, resp.Codespace, strconv.FormatUint(uint64(resp.Code), 10)).Inc()
+	default:
+		m.broadcasts.WithLabelValues(broadcastAccepted, "", "0").Inc()
+		for _, coin := range fee {
+			amount, _ := new(big.Float).SetInt(coin.Amount.BigInt()).Float64()
+			m.fees.WithLabelValues(coin.Denom).Add(amount)
+		}
+	}
+}
+
+// telemetryUnaryInterceptor traces every unary gRPC call as a client span and records it in metrics.
+func telemetryUnaryInterceptor(tracerProvider trace.TracerProvider, metrics *Metrics, chainID string) grpc.UnaryClientInterceptor {
+	tracer := tracerProvider.Tracer(instrumentationName)
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		service, name := splitMethod(method)
+		ctx, span := tracer.Start(ctx, strings.TrimPrefix(method, "/"),
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("rpc.system", "grpc"),
+				attribute.String("rpc.service", service),
+				attribute.String("rpc.method", name),
+				attribute.String("net.peer.name", cc.Target()),
+				attribute.String("cysic.chain_id", chainID),
+			),
+		)
+		defer span.End()
+
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		metrics.observeRPC(method, err, time.Since(start))
+
+		code := status.Code(err)
+		span.SetAttributes(attribute.Int64("rpc.grpc.status_code", int64(code)))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(otelCodes.Error, code.String())
+		}
+
+		return err
+	}
+}
+
+// splitMethod splits a full gRPC method name, e.g. "/cosmos.bank.v1beta1.Query/Balance", into service and method.
+func splitMethod(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+
+	return "", fullMethod
+}
+
+// startTxSpan starts the span of a transaction sent through the pipeline of broadcastMsgs.
+func (s *Server) startTxSpan(ctx context.Context, signer TxSigner, msgList []sdk.Msg, o *txOptions) (context.Context, trace.Span) {
+	msgTypes := make([]string, 0, len(msgList))
+	for _, msg := range msgList {
+		msgTypes = append(msgTypes, sdk.MsgTypeURL(msg))
+	}
+
+	return s.tracer.Start(ctx, "BroadcastMsgs", trace.WithAttributes(
+		attribute.String("cysic.chain_id", s.chainID),
+		attribute.StringSlice("cysic.tx.msg_types", msgTypes),
+		attribute.String("cysic.tx.signer", signer.Address().String()),
+		attribute.String("cysic.tx.sign_mode", o.signMode.String()),
+	))
+}
+
+// startStage starts the span of a stage of the transaction pipeline. The returned function ends
+// it, recording err if not nil, and records the latency of the stage.
+func (s *Server) startStage(ctx context.Context, stage string) (context.Context, func(err error)) {
+	ctx, span := s.tracer.Start(ctx, stage)
+	start := time.Now()
+
+	return ctx, func(err error) {
+		s.metrics.observeStage(stage, time.Since(start))
+		endSpan(span, err)
+	}
+}
+
+// endSpan ends span, recording err if not nil.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelCodes.Error, err.Error())
+	}
+	span.End()
+}
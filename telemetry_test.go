@@ -0,0 +1,191 @@
+package gosdk
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.opentelemetry.io/otel/attribute"
+	otelCodes "go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// spanAttribute returns the value of the attribute key of span.
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+
+	return attribute.Value{}, false
+}
+
+// childSpans returns the ended spans whose parent is parent, in the order they ended.
+func childSpans(spans []sdktrace.ReadOnlySpan, parent trace.SpanContext) []sdktrace.ReadOnlySpan {
+	var children []sdktrace.ReadOnlySpan
+	for _, span := range spans {
+		if span.Parent().SpanID() == parent.SpanID() && span.Parent().TraceID() == parent.TraceID() {
+			children = append(children, span)
+		}
+	}
+
+	return children
+}
+
+func TestTelemetryBroadcast(t *testing.T) {
+	chain := newMockChain()
+	node := chain.serve(t)
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	metrics := NewMetrics("test")
+	if err := prometheus.NewPedanticRegistry().Register(metrics); err != nil {
+		t.Fatalf("register metrics: %v", err)
+	}
+	s := newTestServer(t, node, WithGas(CYSToken, 1), WithTracerProvider(tp), WithMetrics(metrics))
+
+	signer := newTestSigner(t)
+	chain.fund(signer.Address(), testCoins(1_000_000))
+	msg, err := NewSendMsg(signer.Address().String(), newTestSigner(t).Address().String(), CYSToken, sdkmath.NewInt(10))
+	if err != nil {
+		t.Fatalf("new send msg: %v", err)
+	}
+	result, err := s.BroadcastMsgs(signer, []sdk.Msg{msg})
+	if err != nil {
+		t.Fatalf("broadcast: %v", err)
+	}
+
+	fee := chain.includedTx(t, result.TxHash).GetFee()
+	if len(fee) != 1 {
+		t.Fatalf("fee = %v, want one coin", fee)
+	}
+
+	spans := recorder.Ended()
+	var txSpan sdktrace.ReadOnlySpan
+	for _, span := range spans {
+		if span.Name() == "BroadcastMsgs" {
+			txSpan = span
+		}
+	}
+	if txSpan == nil {
+		t.Fatalf("no BroadcastMsgs span among %d spans", len(spans))
+	}
+	if txSpan.Parent().IsValid() {
+		t.Errorf("BroadcastMsgs span has parent %v, want a root span", txSpan.Parent().SpanID())
+	}
+	for key, want := range map[attribute.Key]string{
+		"cysic.chain_id":    testChainID,
+		"cysic.tx.signer":   signer.Address().String(),
+		"cysic.tx.hash":     result.TxHash,
+		"cysic.tx.fee":      fee.String(),
+		"cysic.tx.gas_used": strconv.FormatUint(mockTxGas+mockMsgGas, 10),
+	} {
+		value, ok := spanAttribute(txSpan, key)
+		if !ok || value.Emit() != want {
+			t.Errorf("span attribute %v = %q, want %q", key, value.Emit(), want)
+		}
+	}
+
+	stages := childSpans(spans, txSpan.SpanContext())
+	wantStages := []string{stageAccountLookup, stageFeeQuote, stageGasEstimate, stageSign, stageBroadcast}
+	if len(stages) != len(wantStages) {
+		t.Fatalf("%d stage spans, want %v", len(stages), wantStages)
+	}
+	for i, stage := range stages {
+		if stage.Name() != wantStages[i] || stage.Status().Code == otelCodes.Error {
+			t.Errorf("stage %d = %v (%v), want %v", i, stage.Name(), stage.Status().Code, wantStages[i])
+		}
+	}
+	// the gRPC calls of a stage are its client spans
+	rpcs := childSpans(spans, stages[4].SpanContext())
+	if len(rpcs) != 1 || rpcs[0].Name() != "cosmos.tx.v1beta1.Service/BroadcastTx" || rpcs[0].SpanKind() != trace.SpanKindClient {
+		t.Fatalf("broadcast stage children = %v, want the BroadcastTx client span", rpcs)
+	}
+	if method, _ := spanAttribute(rpcs[0], "rpc.method"); method.AsString() != "BroadcastTx" {
+		t.Errorf("rpc.method = %q, want BroadcastTx", method.AsString())
+	}
+	if code, _ := spanAttribute(rpcs[0], "rpc.grpc.status_code"); code.AsInt64() != 0 {
+		t.Errorf("rpc.grpc.status_code = %d, want 0", code.AsInt64())
+	}
+	if simulate := childSpans(spans, stages[2].SpanContext()); len(simulate) != 1 || simulate[0].Name() != "cosmos.tx.v1beta1.Service/Simulate" {
+		t.Errorf("gas estimate stage children = %v, want the Simulate client span", simulate)
+	}
+
+	if got := testutil.ToFloat64(metrics.broadcasts.WithLabelValues(broadcastAccepted, "", "0")); got != 1 {
+		t.Errorf("accepted broadcasts = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(metrics.fees.WithLabelValues(CYSToken)); got != float64(fee[0].Amount.Int64()) {
+		t.Errorf("fees = %v, want %v", got, fee[0].Amount)
+	}
+	if got := testutil.CollectAndCount(metrics.stageDuration); got != len(wantStages) {
+		t.Errorf("%d stage histograms, want %d", got, len(wantStages))
+	}
+	if got := testutil.CollectAndCount(metrics.rpcErrors); got != 0 {
+		t.Errorf("%d rpc error series, want none", got)
+	}
+	if got := testutil.CollectAndCount(metrics.rpcDuration); got == 0 {
+		t.Error("no rpc duration series")
+	}
+}
+
+func TestTelemetryFailures(t *testing.T) {
+	chain := newMockChain()
+	node := chain.serve(t)
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	metrics := NewMetrics("test")
+	s := newTestServer(t, node, WithFixedGas(), WithRetryPolicy(NoRetry()), WithTracerProvider(tp), WithMetrics(metrics))
+
+	signer := newTestSigner(t)
+	chain.fund(signer.Address(), testCoins(1_000_000))
+	if _, err := s.Send(signer, newTestSigner(t).Address().String(), CYSToken, sdkmath.NewInt(10_000_000)); err == nil {
+		t.Fatal("send more than the balance: got no error")
+	}
+
+	code := strconv.FormatUint(uint64(sdkerrors.ErrInsufficientFunds.ABCICode()), 10)
+	if got := testutil.ToFloat64(metrics.broadcasts.WithLabelValues(broadcastRejected, sdkerrors.RootCodespace, code)); got != 1 {
+		t.Errorf("rejected broadcasts = %v, want 1", got)
+	}
+	if got := testutil.CollectAndCount(metrics.fees); got != 0 {
+		t.Errorf("%d fee series, want none for a rejected tx", got)
+	}
+	var stage sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if span.Name() == stageBroadcast {
+			stage = span
+		}
+	}
+	if stage == nil || stage.Status().Code != otelCodes.Error || len(stage.Events()) == 0 {
+		t.Errorf("broadcast stage span %v, want the error recorded", stage)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := s.AwaitTxCtx(ctx, "0123456789ABCDEF0123456789ABCDEF0123456789ABCDEF0123456789ABCDEF"); err == nil {
+		t.Fatal("await unknown tx: got no error")
+	}
+	if got := testutil.ToFloat64(metrics.rpcErrors.WithLabelValues(getTxMethod, "NotFound")); got < 1 {
+		t.Errorf("GetTx NotFound errors = %v, want at least 1", got)
+	}
+	var getTx sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if span.Name() == "cosmos.tx.v1beta1.Service/GetTx" {
+			getTx = span
+		}
+	}
+	if getTx == nil || getTx.Status().Code != otelCodes.Error || getTx.Status().Description != "NotFound" {
+		t.Errorf("GetTx span %v, want the NotFound status", getTx)
+	}
+}
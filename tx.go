@@ -0,0 +1,231 @@
+package gosdk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkTx "github.com/cosmos/cosmos-sdk/types/tx"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// awaitPollInterval is the wait between two GetTx calls of AwaitTx.
+	awaitPollInterval = time.Second
+	// defaultAwaitTimeout bounds AwaitTx when called without a context.
+	defaultAwaitTimeout = 6 * BlockTime
+)
+
+// TxOption customizes a single transaction.
+type TxOption func(*txOptions)
+
+type txOptions struct {
+	feeStrategy   FeeStrategy
+	broadcastMode BroadcastMode
+	signMode      TxSignMode
+	feePayer      *signerAccount
+	feeGranter    sdk.AccAddress
+}
+
+// newTxOptions applies opts over the Server's defaults.
+func (s *Server) newTxOptions(opts []TxOption) *txOptions {
+	o := &txOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.signMode == SignModeDefault {
+		o.signMode = s.signMode
+	}
+
+	return o
+}
+
+// WithTxFeeStrategy overrides the Server's fee strategy for one transaction.
+//
+// @param strategy the fee strategy
+func WithTxFeeStrategy(strategy FeeStrategy) TxOption {
+	return func(o *txOptions) {
+		o.feeStrategy = strategy
+	}
+}
+
+// BroadcastMode selects when a broadcast returns.
+type BroadcastMode int
+
+const (
+	// BroadcastModeSync returns once the transaction passed CheckTx. This is the default.
+	BroadcastModeSync BroadcastMode = iota
+	// BroadcastModeAsync returns as soon as the node received the transaction, without
+	// waiting for CheckTx; the result carries the hash only.
+	BroadcastModeAsync
+)
+
+// String returns the name of the mode.
+func (m BroadcastMode) String() string {
+	switch m {
+	case BroadcastModeSync:
+		return "sync"
+	case BroadcastModeAsync:
+		return "async"
+	}
+
+	return fmt.Sprintf("BroadcastMode(%d)", int(m))
+}
+
+// proto returns the BroadcastMode of the tx service.
+func (m BroadcastMode) proto() (sdkTx.BroadcastMode, error) {
+	switch m {
+	case BroadcastModeSync:
+		return sdkTx.BroadcastMode_BROADCAST_MODE_SYNC, nil
+	case BroadcastModeAsync:
+		return sdkTx.BroadcastMode_BROADCAST_MODE_ASYNC, nil
+	}
+
+	return sdkTx.BroadcastMode_BROADCAST_MODE_UNSPECIFIED, fmt.Errorf("unknown broadcast mode %v", m)
+}
+
+// WithBroadcastMode sets the broadcast mode of one transaction. In async mode a rejected
+// transaction is only noticed by AwaitTx, and sequence mismatches are not retried.
+//
+// @param mode the broadcast mode
+func WithBroadcastMode(mode BroadcastMode) TxOption {
+	return func(o *txOptions) {
+		o.broadcastMode = mode
+	}
+}
+
+// TxResult is the outcome of a transaction included in a block.
+type TxResult struct {
+	TxHash    string
+	Height    int64
+	GasWanted int64
+	GasUsed   int64
+	// Code is zero if the transaction succeeded; otherwise Codespace and RawLog describe the failure.
+	Code      uint32
+	Codespace string
+	RawLog    string
+	Events    []TxEvent
+}
+
+// TxEvent is an event emitted by a transaction, with its attributes decoded to strings.
+type TxEvent struct {
+	Type       string
+	Attributes []TxEventAttribute
+}
+
+// TxEventAttribute is a key/value pair of a TxEvent.
+type TxEventAttribute struct {
+	Key   string
+	Value string
+}
+
+// Succeeded reports whether the transaction was executed successfully.
+func (r *TxResult) Succeeded() bool {
+	return r.Code == 0
+}
+
+// Err returns the execution error of the transaction, or nil if it succeeded.
+func (r *TxResult) Err() error {
+	if r.Succeeded() {
+		return nil
+	}
+
+	return &TxError{TxHash: r.TxHash, Codespace: r.Codespace, Code: r.Code, Log: r.RawLog}
+}
+
+// Attribute returns the value of the first attribute key of an event of type eventType.
+//
+// @param eventType the event type, e.g. "transfer"
+// @param key the attribute key, e.g. "amount"
+// @return the value and whether it was found
+func (r *TxResult) Attribute(eventType, key string) (string, bool) {
+	for _, event := range r.Events {
+		if event.Type != eventType {
+			continue
+		}
+		for _, attr := range event.Attributes {
+			if attr.Key == key {
+				return attr.Value, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// AwaitTx waits until the transaction txHash is included in a block, for at most one minute.
+//
+// A transaction that was included but failed is not an error: check TxResult.Code or TxResult.Err.
+//
+// @param txHash the hash of the transaction
+// @return the transaction result, or an error if it was not included in time or the query fails
+func (s *Server) AwaitTx(txHash string) (*TxResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultAwaitTimeout)
+	defer cancel()
+
+	return s.AwaitTxCtx(ctx, txHash)
+}
+
+// AwaitTxCtx is like AwaitTx but waits until ctx is done instead of one minute.
+func (s *Server) AwaitTxCtx(ctx context.Context, txHash string) (*TxResult, error) {
+	if err := s.KeepGrpcConnCtx(ctx); err != nil {
+		s.log().Error("error when keep grpc conn", "endpoint", s.endPoint, "err", err)
+		return nil, err
+	}
+
+	txClient := sdkTx.NewServiceClient(s.conn())
+	ticker := time.NewTicker(awaitPollInterval)
+	defer ticker.Stop()
+
+	for {
+		resp, err := txClient.GetTx(ctx, &sdkTx.GetTxRequest{Hash: txHash})
+		if err != nil && !isTxNotFound(err) {
+			s.log().Error("error when get tx", "txHash", txHash, "err", err)
+			return nil, wrapCtxErr(ctx, err)
+		}
+		if err == nil && resp.TxResponse != nil && resp.TxResponse.Height != 0 {
+			// included, even if it failed, so its sequences are used
+			s.sequences.included(resp.TxResponse.TxHash)
+			return newTxResult(resp), nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("tx %v not included: %w: %w", txHash, ErrTxNotFound, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// isTxNotFound reports whether err means the node has not indexed the transaction yet.
+func isTxNotFound(err error) bool {
+	return status.Code(err) == codes.NotFound || strings.Contains(err.Error(), "tx not found")
+}
+
+// newTxResult converts a GetTx response.
+func newTxResult(resp *sdkTx.GetTxResponse) *TxResult {
+	txResp := resp.TxResponse
+	result := &TxResult{
+		TxHash:    txResp.TxHash,
+		Height:    txResp.Height,
+		GasWanted: txResp.GasWanted,
+		GasUsed:   txResp.GasUsed,
+		Code:      txResp.Code,
+		Codespace: txResp.Codespace,
+		RawLog:    txResp.RawLog,
+		Events:    make([]TxEvent, 0, len(txResp.Events)),
+	}
+
+	for _, event := range txResp.Events {
+		attributes := make([]TxEventAttribute, 0, len(event.Attributes))
+		for _, attr := range event.Attributes {
+			attributes = append(attributes, TxEventAttribute{Key: string(attr.Key), Value: string(attr.Value)})
+		}
+		result.Events = append(result.Events, TxEvent{Type: event.Type, Attributes: attributes})
+	}
+
+	return result
+}
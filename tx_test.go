@@ -0,0 +1,190 @@
+package gosdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	bankTypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+const getTxMethod = "/cosmos.tx.v1beta1.Service/GetTx"
+
+func TestAwaitTxIncludedLater(t *testing.T) {
+	chain := newMockChain()
+	node := chain.serve(t)
+	s := newTestServer(t, node)
+
+	signer := newTestSigner(t)
+	recipient := newTestSigner(t).Address().String()
+	chain.fund(signer.Address(), testCoins(1_000_000))
+
+	chain.setIndexing(false)
+	txHash, err := s.Send(signer, recipient, CYSToken, sdkmath.NewInt(10))
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	done := make(chan struct{})
+	var (
+		result   *TxResult
+		awaitErr error
+	)
+	go func() {
+		defer close(done)
+		result, awaitErr = s.AwaitTx(txHash)
+	}()
+	for node.callCount(getTxMethod) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	chain.setIndexing(true)
+	<-done
+
+	if awaitErr != nil {
+		t.Fatalf("await tx: %v", awaitErr)
+	}
+	if n := node.callCount(getTxMethod); n < 2 {
+		t.Errorf("%d GetTx calls, want the tx not found first", n)
+	}
+	if result.TxHash != txHash || result.Height == 0 || !result.Succeeded() || result.Err() != nil {
+		t.Errorf("result = %+v, want the successful tx %v", result, txHash)
+	}
+	if amount, ok := result.Attribute(bankTypes.EventTypeTransfer, sdk.AttributeKeyAmount); !ok || amount != "10"+CYSToken {
+		t.Errorf("transfer amount = %q, %v; want 10%v", amount, ok, CYSToken)
+	}
+	if to, ok := result.Attribute(bankTypes.EventTypeTransfer, bankTypes.AttributeKeyRecipient); !ok || to != recipient {
+		t.Errorf("transfer recipient = %q, %v; want %v", to, ok, recipient)
+	}
+	if _, ok := result.Attribute("message", "action"); ok {
+		t.Error("found an attribute of an event the tx did not emit")
+	}
+}
+
+func TestAwaitTxFailedTx(t *testing.T) {
+	chain := newMockChain()
+	node := chain.serve(t)
+	s := newTestServer(t, node)
+
+	signer := newTestSigner(t)
+	chain.fund(signer.Address(), testCoins(1_000_000))
+
+	chain.failExecution(sdkerrors.ErrInsufficientFunds.Wrap("1CYS is smaller than 10CYS"))
+	txHash, err := s.Send(signer, newTestSigner(t).Address().String(), CYSToken, sdkmath.NewInt(10))
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	// a failed tx is a result, not an error
+	result, err := s.AwaitTx(txHash)
+	if err != nil {
+		t.Fatalf("await tx: %v", err)
+	}
+	if result.Succeeded() || result.Code != sdkerrors.ErrInsufficientFunds.ABCICode() || result.Codespace != sdkerrors.RootCodespace {
+		t.Errorf("result = %+v, want the insufficient funds failure", result)
+	}
+	txErr := result.Err()
+	if !errors.Is(txErr, ErrInsufficientFunds) || !errors.Is(txErr, ErrTxFailed) {
+		t.Errorf("result error %v does not match ErrInsufficientFunds and ErrTxFailed", txErr)
+	}
+	var asTxErr *TxError
+	if !errors.As(txErr, &asTxErr) || asTxErr.TxHash != txHash {
+		t.Errorf("result error %v is not the *TxError of %v", txErr, txHash)
+	}
+	if _, ok := result.Attribute(bankTypes.EventTypeTransfer, sdk.AttributeKeyAmount); ok {
+		t.Error("the failed tx has a transfer event")
+	}
+	if got := chain.account(signer.Address()).sequence; got != 1 {
+		t.Errorf("sequence = %d, want 1 for the included tx", got)
+	}
+}
+
+func TestAwaitTxNotIncluded(t *testing.T) {
+	chain := newMockChain()
+	node := chain.serve(t)
+	s := newTestServer(t, node)
+
+	const unknownHash = "0123456789ABCDEF0123456789ABCDEF0123456789ABCDEF0123456789ABCDEF"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := s.AwaitTxCtx(ctx, unknownHash); !errors.Is(err, ErrTxNotFound) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("timeout: got %v, want ErrTxNotFound and the deadline", err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	calls := node.callCount(getTxMethod)
+	done := make(chan error)
+	go func() {
+		_, err := s.AwaitTxCtx(ctx, unknownHash)
+		done <- err
+	}()
+	// cancel while AwaitTxCtx waits for the next poll
+	for node.callCount(getTxMethod) == calls {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("canceled: got %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("AwaitTxCtx did not return when the context was canceled")
+	}
+}
+
+func TestBroadcastMode(t *testing.T) {
+	chain := newMockChain()
+	node := chain.serve(t)
+	s := newTestServer(t, node, WithFixedGas(), WithRetryPolicy(NoRetry()))
+
+	signer := newTestSigner(t)
+	recipient := newTestSigner(t).Address().String()
+	chain.fund(signer.Address(), testCoins(1_000_000))
+	send := func(amount int64) []sdk.Msg {
+		msg, err := NewSendMsg(signer.Address().String(), recipient, CYSToken, sdkmath.NewInt(amount))
+		if err != nil {
+			t.Fatalf("new send msg: %v", err)
+		}
+		return []sdk.Msg{msg}
+	}
+
+	// sync mode waits for CheckTx and returns its rejection
+	if _, err := s.BroadcastMsgs(signer, send(10_000_000)); !errors.Is(err, ErrInsufficientFunds) {
+		t.Errorf("sync: got %v, want ErrInsufficientFunds", err)
+	}
+
+	// async mode returns the hash before CheckTx: a rejected tx is only noticed by AwaitTx
+	async := WithBroadcastMode(BroadcastModeAsync)
+	rejected, err := s.BroadcastMsgs(signer, send(10_000_000), async)
+	if err != nil {
+		t.Fatalf("async: %v", err)
+	}
+	if rejected.TxHash == "" {
+		t.Error("async: no tx hash")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := s.AwaitTxCtx(ctx, rejected.TxHash); !errors.Is(err, ErrTxNotFound) {
+		t.Errorf("await the rejected async tx: got %v, want ErrTxNotFound", err)
+	}
+
+	accepted, err := s.BroadcastMsgs(signer, send(10), async)
+	if err != nil {
+		t.Fatalf("async: %v", err)
+	}
+	result, err := s.AwaitTx(accepted.TxHash)
+	if err != nil {
+		t.Fatalf("await the accepted async tx: %v", err)
+	}
+	if !result.Succeeded() {
+		t.Errorf("async tx failed: %v", result.Err())
+	}
+	if got := chain.account(signer.Address()).sequence; got != 1 {
+		t.Errorf("sequence = %d, want 1", got)
+	}
+}
@@ -0,0 +1,6 @@
+package types
+
+const (
+	// ModuleName is the name of the fee market module
+	ModuleName = "feemarket"
+)
@@ -82,3 +82,21 @@ func ConvertToETHAddress(addrString string) (string, error) {
 
 	return common.BytesToAddress(addr).Hex(), nil
 }
+
+// toAccAddress converts a hex or bech32 address into an AccAddress.
+//
+// @param addrString the address string to convert
+// @return the account address, or an error if conversion fails
+func toAccAddress(addrString string) (sdk.AccAddress, error) {
+	cosmosAddr, err := ConvertToCysicAddress(addrString)
+	if err != nil {
+		return nil, err
+	}
+
+	accAddr, err := sdk.AccAddressFromBech32(cosmosAddr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address '%s': %w", addrString, err)
+	}
+
+	return accAddr, nil
+}
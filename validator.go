@@ -2,7 +2,6 @@ package gosdk
 
 import (
 	"context"
-	"log"
 
 	"github.com/cosmos/cosmos-sdk/types/query"
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
@@ -15,22 +14,27 @@ import (
 // @param addr the address of the validator
 // @return the validator, or an error if retrieval fails
 func (s *Server) GetValidator(addr string) (stakingtypes.Validator, error) {
+	return s.GetValidatorCtx(context.Background(), addr)
+}
+
+// GetValidatorCtx is like GetValidator but honours ctx for cancellation and deadlines.
+func (s *Server) GetValidatorCtx(ctx context.Context, addr string) (stakingtypes.Validator, error) {
 	var result stakingtypes.Validator
 
-	if err := s.KeepGrpcConn(); err != nil {
-		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+	if err := s.KeepGrpcConnCtx(ctx); err != nil {
+		s.log().Error("error when keep grpc conn", "endpoint", s.endPoint, "err", err)
 		return result, err
 	}
 
-	client := stakingtypes.NewQueryClient(s.Conn)
+	client := stakingtypes.NewQueryClient(s.conn())
 
 	req := &stakingtypes.QueryValidatorRequest{ValidatorAddr: addr}
 
 	opt := make([]grpc.CallOption, 0)
-	resp, err := client.Validator(context.Background(), req, opt...)
+	resp, err := client.Validator(ctx, req, opt...)
 	if err != nil {
-		log.Printf("could not query balances: %v", err)
-		return result, err
+		s.log().Error("could not query validator", "err", err)
+		return result, wrapCtxErr(ctx, err)
 	}
 
 	return resp.Validator, nil
@@ -42,12 +46,17 @@ func (s *Server) GetValidator(addr string) (stakingtypes.Validator, error) {
 // @param pageSize the page size for pagination
 // @return a list of validators, the total count, or an error if retrieval fails
 func (s *Server) GetValidatorList(offset uint64, pageSize uint64) ([]stakingtypes.Validator, uint64, error) {
-	if err := s.KeepGrpcConn(); err != nil {
-		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+	return s.GetValidatorListCtx(context.Background(), offset, pageSize)
+}
+
+// GetValidatorListCtx is like GetValidatorList but honours ctx for cancellation and deadlines.
+func (s *Server) GetValidatorListCtx(ctx context.Context, offset uint64, pageSize uint64) ([]stakingtypes.Validator, uint64, error) {
+	if err := s.KeepGrpcConnCtx(ctx); err != nil {
+		s.log().Error("error when keep grpc conn", "endpoint", s.endPoint, "err", err)
 		return nil, 0, err
 	}
 
-	client := stakingtypes.NewQueryClient(s.Conn)
+	client := stakingtypes.NewQueryClient(s.conn())
 
 	pagination := &query.PageRequest{
 		Offset:     offset,
@@ -60,10 +69,10 @@ func (s *Server) GetValidatorList(offset uint64, pageSize uint64) ([]stakingtype
 	}
 
 	opt := make([]grpc.CallOption, 0)
-	resp, err := client.Validators(context.Background(), req, opt...)
+	resp, err := client.Validators(ctx, req, opt...)
 	if err != nil {
-		log.Printf("could not query balances: %v", err)
-		return nil, 0, err
+		s.log().Error("could not query validators", "err", err)
+		return nil, 0, wrapCtxErr(ctx, err)
 	}
 
 	return resp.Validators, resp.Pagination.Total, nil